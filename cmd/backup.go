@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/betterdiscord/cli/internal/backup"
+	"github.com/betterdiscord/cli/internal/betterdiscord"
+	"github.com/betterdiscord/cli/internal/output"
+)
+
+func init() {
+	backupCreateCmd.Flags().StringP("file", "f", "", "Write the backup to this file instead of the backup folder")
+	backupRestoreCmd.Flags().StringSlice("only", nil, "Only restore these parts (plugins|themes|settings), repeat for several")
+	backupRestoreCmd.Flags().Bool("no-backup", false, "Skip the automatic backup taken before restoring")
+	backupCmd.AddCommand(backupCreateCmd)
+	backupCmd.AddCommand(backupRestoreCmd)
+	backupCmd.AddCommand(backupListCmd)
+	rootCmd.AddCommand(backupCmd)
+}
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up and restore the BetterDiscord folder",
+	Long:  "Create, list, and restore backups of the BetterDiscord folder, including betterdiscord.asar, per-channel settings, plugins, themes, and plugin settings.",
+}
+
+var backupCreateCmd = &cobra.Command{
+	Use:   "create [file]",
+	Short: "Back up the BetterDiscord folder",
+	Long:  "Archive the BetterDiscord folder to a tar.gz file with a manifest of the BetterDiscord version, installed addons, and a checksum of every file. Without a file the backup is saved in the backup folder shown by 'bdcli backup list'. The file can also be given with --file; it is not called --output because -o/--output already selects the output format.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fileFlag, _ := cmd.Flags().GetString("file")
+		if len(args) == 1 {
+			if fileFlag != "" {
+				return fmt.Errorf("give the backup file either as an argument or with --file, not both")
+			}
+			fileFlag = args[0]
+		}
+
+		dest := fileFlag
+		if dest == "" {
+			var err error
+			if dest, err = backup.DefaultPath(backup.ReasonManual); err != nil {
+				return err
+			}
+		}
+
+		bdinstall := betterdiscord.GetInstallation()
+		manifest, err := backup.Create(bdinstall, dest, backup.ReasonManual)
+		if err != nil {
+			return fmt.Errorf("backup failed: %w", err)
+		}
+
+		if output.IsStructured() {
+			return output.Emit(backup.Info{Path: dest, Manifest: *manifest})
+		}
+		output.Printf("✅ Backed up %s to %s\n", bdinstall.Root(), dest)
+		output.Printf("   BetterDiscord %s, %d plugin(s), %d theme(s), %d file(s)\n", output.FormatVersion(manifest.Buildinfo.Version), len(manifest.Plugins), len(manifest.Themes), len(manifest.Files))
+		return nil
+	},
+}
+
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Restore the BetterDiscord folder from a backup",
+	Long:  "Verify a backup against its manifest and restore its files into the BetterDiscord folder. Use --only to restore just plugins, themes, or settings. Files that are not in the backup are left alone. The current folder is backed up first, so a restore can itself be undone; pass --no-backup to skip that.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		onlyFlag, _ := cmd.Flags().GetStringSlice("only")
+
+		bdinstall := betterdiscord.GetInstallation()
+		output.Printf("🔍 Verifying %s...\n", args[0])
+		if _, err := backup.Verify(args[0]); err != nil {
+			return fmt.Errorf("restore failed: %w", err)
+		}
+		// The backup being restored may be the oldest automatic one, so it is kept
+		if err := autoBackup(cmd, bdinstall, backup.ReasonRestore, args[0]); err != nil {
+			return err
+		}
+
+		manifest, restored, err := backup.Restore(bdinstall, args[0], onlyFlag)
+		if err != nil {
+			return fmt.Errorf("restore failed: %w", err)
+		}
+
+		if output.IsStructured() {
+			return output.Emit(map[string]any{"manifest": manifest, "restored": restored})
+		}
+		parts := "everything"
+		if len(onlyFlag) > 0 {
+			parts = strings.Join(onlyFlag, ", ")
+		}
+		output.Printf("✅ Restored %d file(s) (%s) from the backup taken %s\n", len(restored), parts, manifest.Created.Local().Format(output.DateTimeFormat))
+		output.Println("💡 Restart Discord to load the restored files")
+		return nil
+	},
+}
+
+var backupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List backups in the backup folder",
+	Long:  "List the backups in the backup folder, including the automatic backups taken before 'bdcli update', 'bdcli uninstall --full', and 'bdcli backup restore'.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		backups, err := backup.List()
+		if err != nil {
+			return err
+		}
+		if output.IsStructured() {
+			if backups == nil {
+				backups = []backup.Info{}
+			}
+			return output.Emit(backups)
+		}
+		if len(backups) == 0 {
+			output.Println("📭 No backups found.")
+			return nil
+		}
+
+		tw := output.NewTableWriter()
+		fmt.Fprintln(tw, "FILE\tCREATED\tREASON\tVERSION\tPLUGINS\tTHEMES\tSIZE (KB)")
+		for _, info := range backups {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%d\t%.1f\n", filepath.Base(info.Path), info.Created.Local().Format(output.DateTimeFormat), info.Reason, output.FormatVersion(info.Buildinfo.Version), len(info.Plugins), len(info.Themes), float64(info.Size)/1024.0)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		dir, err := backup.Dir()
+		if err == nil {
+			output.Blank()
+			output.Printf("📁 Backup folder: %s\n", dir)
+		}
+		return nil
+	},
+}
+
+// autoBackup takes an automatic backup of bdinstall before a risky change,
+// unless --no-backup was passed. The backups in keep are never pruned.
+func autoBackup(cmd *cobra.Command, bdinstall *betterdiscord.BDInstall, reason string, keep ...string) error {
+	if skip, _ := cmd.Flags().GetBool("no-backup"); skip {
+		return nil
+	}
+
+	dest, err := backup.Auto(bdinstall, reason, keep...)
+	if err != nil {
+		return fmt.Errorf("automatic backup failed, pass --no-backup to continue without one: %w", err)
+	}
+	if dest != "" {
+		output.Printf("💾 Backed up %s to %s\n", bdinstall.Root(), dest)
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/betterdiscord/cli/internal/config"
+	"github.com/betterdiscord/cli/internal/dryrun"
+	"github.com/betterdiscord/cli/internal/output"
+)
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configListCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage persistent CLI settings",
+	Long:  "Read and write settings in the bdcli config file. Flags take precedence over BDCLI_* environment variables, which take precedence over the config file.",
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the value of a setting",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := configEntries()
+		if err != nil {
+			return err
+		}
+		key, err := config.FindKey(args[0])
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			if entry.Key != key.Name {
+				continue
+			}
+			if output.IsStructured() {
+				return output.Emit(entry)
+			}
+			output.Println(entry.Value)
+		}
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value> [value...]",
+	Short: "Store a setting in the config file",
+	Long:  "Store a setting in the config file. List settings such as discord.paths accept several values.",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateConfig(func(cfg *config.Config) error {
+			return cfg.Set(args[0], strings.Join(args[1:], ","))
+		}, "✅ Set %s\n", args[0])
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a setting from the config file",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateConfig(func(cfg *config.Config) error {
+			return cfg.Unset(args[0])
+		}, "✅ Unset %s\n", args[0])
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all settings and where their values come from",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := configEntries()
+		if err != nil {
+			return err
+		}
+		if output.IsStructured() {
+			return output.Emit(entries)
+		}
+
+		path, _ := config.Path()
+		output.Printf("📄 Config file: %s\n\n", path)
+
+		tw := output.NewTableWriter()
+		fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE\tDESCRIPTION")
+		for _, entry := range entries {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", entry.Key, entry.Value, entry.Source, entry.Description)
+		}
+		return tw.Flush()
+	},
+}
+
+// configEntry is a single setting as reported by config get and list.
+type configEntry struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Source      string `json:"source"`
+	Env         string `json:"env,omitempty"`
+	Description string `json:"description"`
+}
+
+// configEntries resolves every setting from the environment and config file.
+func configEntries() ([]configEntry, error) {
+	path, err := config.Path()
+	if err != nil {
+		return nil, err
+	}
+	file, err := config.LoadFile(path)
+	if err != nil {
+		fmt.Fprintf(output.ErrorWriter(), "⚠️  %v\n", err)
+		fmt.Fprintln(output.ErrorWriter(), "💡 Fix the file by hand, or replace it with: bdcli config set <key> <value>")
+		file = &config.Config{}
+	}
+	effective := *file
+	if err := effective.ApplyEnv(); err != nil {
+		fmt.Fprintf(output.ErrorWriter(), "⚠️  %v\n", err)
+	}
+
+	entries := make([]configEntry, 0, len(config.Keys))
+	for _, key := range config.Keys {
+		value, _ := effective.Get(key.Name)
+		fileValue, _ := file.Get(key.Name)
+
+		source := "default"
+		if key.Env != "" && strings.TrimSpace(os.Getenv(key.Env)) != "" {
+			source = "env"
+		} else if fileValue != "" {
+			source = "file"
+		}
+
+		entries = append(entries, configEntry{
+			Key:         key.Name,
+			Value:       value,
+			Source:      source,
+			Env:         key.Env,
+			Description: key.Description,
+		})
+	}
+	return entries, nil
+}
+
+// updateConfig applies change to the config file and saves it. A config file
+// that does not parse is only moved aside once change has succeeded, so a
+// mistyped key or value never leaves the user without their file.
+func updateConfig(change func(*config.Config) error, format string, a ...any) error {
+	path, err := config.Path()
+	if err != nil {
+		return err
+	}
+	cfg, loadErr := config.LoadFile(path)
+	if loadErr != nil {
+		cfg = &config.Config{}
+	}
+	if err := change(cfg); err != nil {
+		return err
+	}
+	if loadErr != nil {
+		// Start over rather than leave the config unusable, keeping the broken file
+		invalid := path + ".invalid"
+		if !dryrun.Skip(dryrun.Move, invalid, "from "+path) {
+			if err := os.Rename(path, invalid); err != nil {
+				return fmt.Errorf("failed to move the invalid config file aside: %w", err)
+			}
+		}
+		output.Printf("⚠️  The config file was invalid and was moved to %s\n", invalid)
+	}
+	if err := cfg.Save(path); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	output.Printf(format, a...)
+	return nil
+}
@@ -31,37 +31,91 @@ var discoverInstallsCmd = &cobra.Command{
 	Long:  "Lists detected Discord installations by channel, showing path, version, install type, and BetterDiscord status.",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		installs := discord.GetAllInstalls()
+		channels := []models.DiscordChannel{models.Stable, models.PTB, models.Canary}
+
+		if output.IsStructured() {
+			docs := []installDocument{}
+			for _, ch := range channels {
+				for _, inst := range installs[ch] {
+					docs = append(docs, newInstallDocument(inst))
+				}
+			}
+			return output.Emit(docs)
+		}
+
 		if len(installs) == 0 {
 			output.Println("📭 No Discord installations detected.")
 			return nil
 		}
 
-		channels := []models.DiscordChannel{models.Stable, models.PTB, models.Canary}
 		output.Printf("🔎 Discord installations:\n\n")
 		tw := output.NewTableWriter()
 		fmt.Fprintln(tw, "CHANNEL\tVERSION\tTYPE\tBD INJECTED\tPATH")
 
+		var stale, unrecognized []*discord.DiscordInstall
 		for _, ch := range channels {
 			arr := installs[ch]
 			for _, inst := range arr {
-				typeLabel := "native"
-				if inst.IsFlatpak {
-					typeLabel = "flatpak"
-				} else if inst.IsSnap {
-					typeLabel = "snap"
-				}
 				bdStatus := "no"
-				if inst.IsInjected() {
+				switch inst.InjectionStatus().State {
+				case discord.InjectionCurrent:
 					bdStatus = "yes"
+				case discord.InjectionStale:
+					bdStatus = "stale"
+					stale = append(stale, inst)
+				}
+				if inst.IndexUnrecognized() {
+					unrecognized = append(unrecognized, inst)
 				}
-				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", ch.Name(), inst.Version, typeLabel, bdStatus, inst.CorePath)
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", ch.Name(), inst.Version, inst.Type(), bdStatus, inst.CorePath)
 			}
 		}
 
-		return tw.Flush()
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+
+		if len(stale) > 0 {
+			output.Blank()
+			for _, inst := range stale {
+				output.Printf("⚠️  %s injection is stale: %s\n", inst.Channel.Name(), inst.InjectionStatus().Reason)
+			}
+			output.Println("💡 Run 'bdcli install' to upgrade the injection in place")
+		}
+		if len(unrecognized) > 0 {
+			output.Blank()
+			for _, inst := range unrecognized {
+				output.Printf("⚠️  %s index.js matches neither the BetterDiscord injection nor the original saved at install\n", inst.Channel.Name())
+				output.Printf("   %s\n", inst.CorePath)
+			}
+			output.Println("💡 Another tool or a Discord update may have replaced it")
+		}
+		return nil
 	},
 }
 
+// installDocument is the structured representation of a detected Discord install.
+type installDocument struct {
+	*discord.DiscordInstall
+	Channel           string                  `json:"channel"`
+	Type              string                  `json:"type"`
+	Injected          bool                    `json:"injected"`
+	Injection         discord.InjectionStatus `json:"injection"`
+	IndexUnrecognized bool                    `json:"indexUnrecognized"`
+}
+
+func newInstallDocument(inst *discord.DiscordInstall) installDocument {
+	status := inst.InjectionStatus()
+	return installDocument{
+		DiscordInstall:    inst,
+		Channel:           inst.Channel.String(),
+		Type:              inst.Type(),
+		Injected:          status.State != discord.InjectionNone,
+		Injection:         status,
+		IndexUnrecognized: inst.IndexUnrecognized(),
+	}
+}
+
 var discoverPathsCmd = &cobra.Command{
 	Use:   "paths",
 	Short: "Show suggested install paths per channel",
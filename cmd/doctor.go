@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/betterdiscord/cli/internal/doctor"
+	"github.com/betterdiscord/cli/internal/output"
+)
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose problems with Discord and BetterDiscord",
+	Long:  "Runs a suite of checks against the detected Discord installs and the BetterDiscord folder, and suggests how to fix anything that fails. Exits non-zero if any check fails.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output.Println("🩺 Running checks...")
+		output.Blank()
+
+		report := doctor.Run()
+
+		if output.IsStructured() {
+			if err := output.Emit(report); err != nil {
+				return err
+			}
+		} else {
+			tw := output.NewTableWriter()
+			fmt.Fprintln(tw, "STATUS\tCHECK\tRESULT")
+			for _, result := range report.Results {
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", doctorStatusLabel(result.Status), result.Check, result.Message)
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+
+			hasHints := false
+			for _, result := range report.Results {
+				if result.Status == doctor.StatusPass || result.Hint == "" {
+					continue
+				}
+				if !hasHints {
+					output.Blank()
+					hasHints = true
+				}
+				output.Printf("💡 %s: %s\n", result.Check, result.Hint)
+			}
+
+			output.Blank()
+			output.Printf("%d passed, %d warning(s), %d failure(s)\n", report.Passed, report.Warnings, report.Failures)
+		}
+
+		if report.Failures > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("doctor found %d failing check(s)", report.Failures)
+		}
+		return nil
+	},
+}
+
+func doctorStatusLabel(status doctor.Status) string {
+	switch status {
+	case doctor.StatusPass:
+		return "✅ pass"
+	case doctor.StatusWarn:
+		return "⚠️  warn"
+	default:
+		return "❌ fail"
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/betterdiscord/cli/internal/betterdiscord"
+	"github.com/betterdiscord/cli/internal/output"
 )
 
 func init() {
@@ -23,8 +24,42 @@ var infoCmd = &cobra.Command{
 			return fmt.Errorf("BetterDiscord does not appear to be installed, try running 'bdcli install' first")
 		}
 
+		if output.IsStructured() {
+			return output.Emit(newInfoDocument(bdinstall))
+		}
+
 		bdinstall.LogBuildinfo()
 
 		return nil
 	},
 }
+
+// infoDocument is the structured representation of a BetterDiscord installation.
+type infoDocument struct {
+	Buildinfo *betterdiscord.Buildinfo `json:"buildinfo,omitempty"`
+	Paths     infoPaths                `json:"paths"`
+}
+
+type infoPaths struct {
+	Root    string `json:"root"`
+	Data    string `json:"data"`
+	Asar    string `json:"asar"`
+	Plugins string `json:"plugins"`
+	Themes  string `json:"themes"`
+}
+
+func newInfoDocument(bdinstall *betterdiscord.BDInstall) infoDocument {
+	doc := infoDocument{
+		Paths: infoPaths{
+			Root:    bdinstall.Root(),
+			Data:    bdinstall.Data(),
+			Asar:    bdinstall.Asar(),
+			Plugins: bdinstall.Plugins(),
+			Themes:  bdinstall.Themes(),
+		},
+	}
+	if buildinfo, err := bdinstall.ReadBuildinfo(); err == nil {
+		doc.Buildinfo = &buildinfo
+	}
+	return doc
+}
@@ -3,6 +3,8 @@ package cmd
 import (
 	"fmt"
 	"path"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -13,7 +15,11 @@ import (
 
 func init() {
 	installCmd.Flags().StringP("path", "p", "", "Path to a Discord installation")
-	installCmd.Flags().StringP("channel", "c", "stable", "Discord release channel (stable|ptb|canary)")
+	installCmd.Flags().StringSliceP("channel", "c", []string{"stable"}, "Discord release channel (stable|ptb|canary), repeat to install to several")
+	installCmd.Flags().BoolP("all", "a", false, "Install BetterDiscord to all detected Discord installations")
+	installCmd.Flags().StringP("type", "t", "", "Only consider installs of this type (native|flatpak|snap)")
+	installCmd.Flags().Bool("restart", true, "Restart Discord after installing")
+	installCmd.Flags().String("expect-sha256", "", "Abort unless the downloaded betterdiscord.asar has this SHA-256 digest")
 	rootCmd.AddCommand(installCmd)
 }
 
@@ -21,57 +27,212 @@ var installCmd = &cobra.Command{
 	Use:     "install",
 	Aliases: []string{"reinstall"},
 	Short:   "Installs BetterDiscord to your Discord",
-	Long:    "Install BetterDiscord by specifying either --path to a Discord install or --channel to auto-detect (default: stable).",
+	Long:    "Install BetterDiscord by specifying either --path to a Discord install or --channel to auto-detect (default: stable). Repeat --channel or pass --all to install to several Discord installations at once.",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		pathFlag, _ := cmd.Flags().GetString("path")
-		channelFlag, _ := cmd.Flags().GetString("channel")
+		channelFlags := channelsOption(cmd)
+		allFlag, _ := cmd.Flags().GetBool("all")
+		expectFlag, _ := cmd.Flags().GetString("expect-sha256")
+
+		typeFlag, err := typeOption(cmd)
+		if err != nil {
+			return err
+		}
+
+		applyRestartFlag(cmd)
 
 		pathProvided := pathFlag != ""
 		channelProvided := cmd.Flags().Changed("channel")
 
+		if allFlag && (pathProvided || channelProvided) {
+			return fmt.Errorf("--all cannot be used with --path or --channel")
+		}
+
 		if pathProvided && channelProvided {
 			return fmt.Errorf("--path and --channel are mutually exclusive")
 		}
 
-		var install *discord.DiscordInstall
+		if pathProvided && typeFlag != "" {
+			return fmt.Errorf("--path and --type are mutually exclusive")
+		}
 
 		if pathProvided {
-			install = discord.ResolvePath(pathFlag)
+			install := discord.ResolvePath(pathFlag)
 			if install == nil {
 				return fmt.Errorf("could not find a valid Discord installation at %s", pathFlag)
 			}
-		} else {
-			channel := models.ParseChannel(channelFlag)
-			corePath := discord.GetSuggestedPath(channel)
-			install = discord.ResolvePath(corePath)
+			return installSingle(install, expectFlag)
+		}
+
+		if !allFlag && len(channelFlags) == 1 {
+			install, err := selectInstall(models.ParseChannel(channelFlags[0]), typeFlag)
+			if err != nil {
+				return err
+			}
 			if install == nil {
-				return fmt.Errorf("could not find a valid %s installation to install to", channelFlag)
+				return fmt.Errorf("could not find a valid %s installation to install to", strings.TrimSpace(channelFlags[0]+" "+typeFlag))
 			}
+			return installSingle(install, expectFlag)
 		}
 
-		if err := install.InstallBD(); err != nil {
-			return fmt.Errorf("installation failed: %w", err)
+		var targets []installTarget
+		if allFlag {
+			for _, install := range filterInstallType(getAllInstalls(), typeFlag) {
+				targets = append(targets, installTarget{channel: install.Channel, install: install})
+			}
+			if len(targets) == 0 {
+				return fmt.Errorf("could not find any Discord installations to install to")
+			}
+		} else {
+			targets, err = channelTargets(channelFlags, typeFlag)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Failures are reported in the summary, usage would only bury it
+		cmd.SilenceUsage = true
+		results, err := installMany(targets, expectFlag)
+		if err != nil {
+			return err
 		}
+		return reportInstalls(results)
+	},
+}
+
+// installTarget is an install selected for installation. install is nil
+// when no installation was found for the channel.
+type installTarget struct {
+	channel models.DiscordChannel
+	install *discord.DiscordInstall
+}
+
+// installResult is the outcome of installing to one Discord installation.
+type installResult struct {
+	Channel  string `json:"channel"`
+	Version  string `json:"version,omitempty"`
+	Type     string `json:"type,omitempty"`
+	CorePath string `json:"corePath,omitempty"`
+	Success  bool   `json:"success"`
+	Error    string `json:"error,omitempty"`
+}
+
+func installSingle(install *discord.DiscordInstall, expectSHA256 string) error {
+	if err := install.GetBetterDiscordInstall().SetExpectedSHA256(expectSHA256); err != nil {
+		return err
+	}
+
+	if err := install.InstallBD(); err != nil {
+		return fmt.Errorf("installation failed: %w", err)
+	}
 
-		output.Printf("✅ BetterDiscord installed to %s\n", path.Dir(install.CorePath))
+	output.Printf("✅ BetterDiscord installed to %s\n", path.Dir(install.CorePath))
+	output.Blank()
+	output.Printf("📋 Installation Summary:\n")
+	output.Blank()
+	output.Printf("   Release Channel: %s\n", install.Channel.Display())
+	output.Printf("   Discord Version: %s\n", install.Version)
+	output.Printf("   Install Type:    %s\n", install.Type())
+	output.Printf("   Core Path:       %s\n", path.Dir(install.CorePath))
+	output.Blank()
+
+	bdinstall := install.GetBetterDiscordInstall()
+	bdinstall.LogBuildinfo()
+	return nil
+}
+
+// channelTargets selects the install of each channel, skipping channels
+// listed more than once.
+func channelTargets(channels []string, installType string) ([]installTarget, error) {
+	var targets []installTarget
+	var seen []models.DiscordChannel
+	for _, name := range channels {
+		channel := models.ParseChannel(name)
+		if slices.Contains(seen, channel) {
+			continue
+		}
+		seen = append(seen, channel)
+
+		install, err := selectInstall(channel, installType)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, installTarget{channel: channel, install: install})
+	}
+	return targets, nil
+}
+
+// installMany installs to every target, carrying on past failures. The asar
+// is only downloaded once per BetterDiscord folder.
+func installMany(targets []installTarget, expectSHA256 string) ([]installResult, error) {
+	var results []installResult
+	for _, target := range targets {
+		result := installResult{Channel: target.channel.String()}
+		if target.install == nil {
+			result.Error = "no installation found"
+			output.Printf("❌ Could not find a valid %s installation\n", target.channel.Display())
+			output.Blank()
+			results = append(results, result)
+			continue
+		}
+
+		install := target.install
+		result.Version = install.Version
+		result.Type = install.Type()
+		result.CorePath = install.CorePath
+
+		if err := install.GetBetterDiscordInstall().SetExpectedSHA256(expectSHA256); err != nil {
+			return nil, err
+		}
+
+		output.Printf("📦 Installing to %s %s (%s)\n", install.Channel.Name(), install.Version, install.Type())
 		output.Blank()
-		output.Printf("📋 Installation Summary:\n")
+		if err := install.InstallBD(); err != nil {
+			result.Error = err.Error()
+			output.Printf("❌ Failed to install to %s\n", path.Dir(install.CorePath))
+			output.Printf("   %s\n", err.Error())
+			output.Blank()
+		} else {
+			result.Success = true
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// reportInstalls prints the per-install summary and fails if any install did.
+func reportInstalls(results []installResult) error {
+	failed := 0
+	for _, result := range results {
+		if !result.Success {
+			failed++
+		}
+	}
+
+	if output.IsStructured() {
+		if err := output.Emit(results); err != nil {
+			return err
+		}
+	} else {
+		output.Println("📋 Installation Summary:")
 		output.Blank()
-		output.Printf("   Release Channel: %s\n", install.Channel.Display())
-		output.Printf("   Discord Version: %s\n", install.Version)
-		output.Printf("   Install Type:    %s\n", func() string {
-			if install.IsFlatpak {
-				return "flatpak"
-			} else if install.IsSnap {
-				return "snap"
+		tw := output.NewTableWriter()
+		fmt.Fprintln(tw, "CHANNEL\tVERSION\tTYPE\tPATH\tRESULT")
+		for _, result := range results {
+			status := "✅ installed"
+			if !result.Success {
+				status = "❌ " + result.Error
 			}
-			return "native"
-		}())
-		output.Printf("   Core Path:       %s\n", path.Dir(install.CorePath))
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", result.Channel, result.Version, result.Type, result.CorePath, status)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
 		output.Blank()
+	}
 
-		bdinstall := install.GetBetterDiscordInstall()
-		bdinstall.LogBuildinfo()
-		return nil
-	},
+	if failed > 0 {
+		return fmt.Errorf("installation failed for %d of %d Discord installations", failed, len(results))
+	}
+	return nil
 }
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/betterdiscord/cli/internal/betterdiscord"
+	"github.com/betterdiscord/cli/internal/output"
+)
+
+func init() {
+	lockCmd.Flags().StringP("file", "f", betterdiscord.DefaultLockfileName, "Path to the lockfile")
+	lockUpdateCmd.Flags().StringP("file", "f", betterdiscord.DefaultLockfileName, "Path to the lockfile")
+	syncCmd.Flags().StringP("file", "f", betterdiscord.DefaultLockfileName, "Path to the lockfile")
+	lockCmd.AddCommand(lockUpdateCmd)
+	rootCmd.AddCommand(lockCmd)
+	rootCmd.AddCommand(syncCmd)
+}
+
+var lockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Write a lockfile of installed plugins and themes",
+	Long:  "Records the store ID, version, source URL, and content hash of every installed plugin and theme so the set can be reproduced with 'bdcli sync'. A copy of every file is kept in the bdcli cache folder, because store sources only serve the latest release.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fileFlag, _ := cmd.Flags().GetString("file")
+
+		output.Println("🔒 Generating lockfile...")
+		lock, err := betterdiscord.GenerateLockfile()
+		if err != nil {
+			return fmt.Errorf("failed to generate lockfile: %w", err)
+		}
+
+		if err := lock.Write(fileFlag); err != nil {
+			return fmt.Errorf("failed to write lockfile: %w", err)
+		}
+
+		if output.IsStructured() {
+			return output.Emit(lock)
+		}
+
+		output.Printf("✅ Locked %d plugin(s) and %d theme(s) to %s\n", len(lock.Plugins), len(lock.Themes), fileFlag)
+		return nil
+	},
+}
+
+var lockUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update locked addons to the store's current release and lock them again",
+	Long:  "Installs the release the store serves now for every addon in the lockfile that came from the store, then writes the lockfile again from the installed addons. Use it when 'bdcli sync' fails because a source no longer serves the locked version.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fileFlag, _ := cmd.Flags().GetString("file")
+
+		lock, err := betterdiscord.ReadLockfile(fileFlag)
+		if err != nil {
+			return fmt.Errorf("failed to read lockfile: %w", err)
+		}
+
+		output.Printf("🔄 Updating addons in %s...\n", fileFlag)
+		fresh, updated, err := betterdiscord.UpdateLockfile(lock)
+		if err != nil {
+			return fmt.Errorf("lock update failed: %w", err)
+		}
+		if err := fresh.Write(fileFlag); err != nil {
+			return fmt.Errorf("failed to write lockfile: %w", err)
+		}
+
+		if output.IsStructured() {
+			return output.Emit(map[string]any{"lockfile": fresh, "updated": updated})
+		}
+		output.Printf("✅ Updated %d addon(s) and locked %d plugin(s) and %d theme(s) to %s\n", len(updated), len(fresh.Plugins), len(fresh.Themes), fileFlag)
+		return nil
+	},
+}
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Make installed plugins and themes match a lockfile",
+	Long:  "Installs, updates, and removes plugins and themes until they match the lockfile exactly. Locked files are restored from the local copy kept by 'bdcli lock' when there is one, and otherwise downloaded and checked against the locked hash. Addons whose source no longer serves the locked content are reported as failures; 'bdcli lock update' pins the current release instead.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fileFlag, _ := cmd.Flags().GetString("file")
+
+		lock, err := betterdiscord.ReadLockfile(fileFlag)
+		if err != nil {
+			return fmt.Errorf("failed to read lockfile: %w", err)
+		}
+
+		output.Printf("🔄 Syncing addons with %s...\n", fileFlag)
+		result, err := betterdiscord.SyncLockfile(lock)
+		if err != nil {
+			return fmt.Errorf("sync failed: %w", err)
+		}
+
+		if output.IsStructured() {
+			if err := output.Emit(result); err != nil {
+				return err
+			}
+		} else {
+			output.Printf("\n📊 Summary: %d installed, %d updated, %d removed, %d unchanged, %d failed\n",
+				len(result.Installed), len(result.Updated), len(result.Removed), len(result.Unchanged), len(result.Failed))
+		}
+
+		if len(result.Failed) > 0 {
+			return fmt.Errorf("%d addon(s) could not be synced", len(result.Failed))
+		}
+		return nil
+	},
+}
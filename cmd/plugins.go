@@ -1,10 +1,18 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
 
 	"github.com/betterdiscord/cli/internal/betterdiscord"
+	"github.com/betterdiscord/cli/internal/dryrun"
+	"github.com/betterdiscord/cli/internal/models"
 	"github.com/betterdiscord/cli/internal/output"
+	"github.com/betterdiscord/cli/internal/semver"
 	"github.com/betterdiscord/cli/internal/utils"
 	"github.com/spf13/cobra"
 )
@@ -15,14 +23,30 @@ func initPluginsCmd() {
 	pluginsCmd.AddCommand(pluginsInfoCmd)
 	pluginsCmd.AddCommand(pluginsInstallCmd)
 	pluginsCmd.AddCommand(pluginsRemoveCmd)
+	pluginsCmd.AddCommand(pluginsEnableCmd)
+	pluginsCmd.AddCommand(pluginsDisableCmd)
+	pluginsCmd.AddCommand(pluginsConfigCmd)
+	pluginsConfigCmd.AddCommand(pluginsConfigExportCmd)
+	pluginsConfigCmd.AddCommand(pluginsConfigImportCmd)
 	pluginsCmd.AddCommand(pluginsUpdateCmd)
+	pluginsCmd.AddCommand(pluginsHistoryCmd)
+	pluginsCmd.AddCommand(pluginsRollbackCmd)
 	rootCmd.AddCommand(pluginsCmd)
 }
 
 func init() {
 	initPluginsCmd()
+	pluginsListCmd.Flags().StringP("channel", "c", "", "Only show whether plugins are enabled for this Discord release channel (stable|ptb|canary)")
+	pluginsInstallCmd.Flags().Bool("link", false, "Symlink a local plugin file instead of copying it")
+	pluginsEnableCmd.Flags().StringP("channel", "c", "stable", "Discord release channel (stable|ptb|canary)")
+	pluginsDisableCmd.Flags().StringP("channel", "c", "stable", "Discord release channel (stable|ptb|canary)")
+	pluginsConfigExportCmd.Flags().StringP("file", "f", "", "Write the settings to this file instead of printing them")
+	pluginsRemoveCmd.Flags().Bool("purge", false, "Also delete the plugin's saved settings")
 	pluginsUpdateCmd.Flags().BoolP("check", "c", false, "Check for available updates without installing")
 	pluginsUpdateCmd.Flags().BoolP("all", "a", false, "Update all installed plugins")
+	pluginsUpdateCmd.Flags().Bool("allow-downgrade", false, "Replace addons that are newer than the store release")
+	pluginsUpdateCmd.Flags().IntP("jobs", "j", 4, "Number of concurrent checks and downloads when using --all")
+	pluginsRollbackCmd.Flags().String("version", "", "Version to restore (default: most recent previous version)")
 }
 
 var pluginsCmd = &cobra.Command{
@@ -35,25 +59,19 @@ var pluginsListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List installed plugins",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		items, err := betterdiscord.ListAddons(betterdiscord.AddonPlugin)
+		channels := listChannelsOption(cmd)
+		items, err := betterdiscord.GetInstallation().ListAddonStatus(betterdiscord.AddonPlugin, channels)
 		if err != nil {
 			return err
 		}
+		if output.IsStructured() {
+			return output.Emit(items)
+		}
 		if len(items) == 0 {
 			output.Println("📭 No plugins installed.")
 			return nil
 		}
-
-		tw := output.NewTableWriter()
-		fmt.Fprintln(tw, "NAME\tVERSION\tAUTHOR\tSIZE (KB)\tMODIFIED")
-		for _, item := range items {
-			name := item.Meta.Name
-			if name == "" {
-				name = item.BaseName
-			}
-			fmt.Fprintf(tw, "%s\t%s\t%s\t%.1f\t%s\n", name, item.Meta.Version, item.Meta.Author, float64(item.Size)/1024.0, item.Modified.Format(output.DateTimeFormat))
-		}
-		return tw.Flush()
+		return writeAddonStatus(items, channels)
 	},
 }
 
@@ -75,13 +93,15 @@ var pluginsInfoCmd = &cobra.Command{
 }
 
 var pluginsInstallCmd = &cobra.Command{
-	Use:   "install <name|id|url>",
-	Short: "Install a plugin by name, ID, or direct URL",
+	Use:   "install <name|id|url|path|github:owner/repo>",
+	Short: "Install a plugin from the store, a URL, a local file, GitHub, or a .zip archive",
+	Long:  "Install a plugin by store name or ID, or from a direct URL, a local file, a github:owner/repo[@ref][/path] source, or a .zip archive holding one or more plugins. Without a path, github: sources install the plugin files or .zip archive attached to the latest release, or to the release tagged ref. The ref cannot contain '/', and a path must name a plugin file or .zip archive. Use --link to symlink a local file instead of copying it.",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		identifier := args[0]
-		// Check if not a URL and already installed
-		if !utils.IsURL(identifier) {
+		linkFlag, _ := cmd.Flags().GetBool("link")
+		// Check if not a URL, file, or GitHub source and already installed
+		if !betterdiscord.IsAddonSource(identifier) {
 			if existing := betterdiscord.FindAddon(betterdiscord.AddonPlugin, identifier); existing != nil {
 				name := existing.Meta.Name
 				if name == "" {
@@ -92,12 +112,12 @@ var pluginsInstallCmd = &cobra.Command{
 				return nil
 			}
 		}
-		resolved, err := betterdiscord.InstallAddon(betterdiscord.AddonPlugin, identifier)
-		if err != nil {
-			return err
+		resolved, err := betterdiscord.InstallAddonWith(betterdiscord.AddonPlugin, identifier, betterdiscord.InstallOptions{Link: linkFlag})
+		// Files written before a failure are reported along with it
+		for _, path := range installedPaths(resolved) {
+			output.Printf("✅ Plugin installed at %s\n", path)
 		}
-		output.Printf("✅ Plugin installed at %s\n", resolved.Path)
-		return nil
+		return err
 	},
 }
 
@@ -114,7 +134,8 @@ var pluginsRemoveCmd = &cobra.Command{
 			output.Printf("❌ Plugin '%s' is not installed.\n", identifier)
 			return nil
 		}
-		if err := betterdiscord.RemoveAddon(betterdiscord.AddonPlugin, identifier); err != nil {
+		purgeFlag, _ := cmd.Flags().GetBool("purge")
+		if err := betterdiscord.RemoveAddon(betterdiscord.AddonPlugin, existing.FullFilename); err != nil {
 			return err
 		}
 		name := existing.Meta.Name
@@ -122,6 +143,280 @@ var pluginsRemoveCmd = &cobra.Command{
 			name = existing.BaseName
 		}
 		output.Printf("✅ Plugin removed: %s\n", name)
+
+		if purgeFlag {
+			removed, err := betterdiscord.RemovePluginConfig(name)
+			if err != nil {
+				return fmt.Errorf("failed to remove settings for %s: %w", name, err)
+			}
+			if removed {
+				output.Printf("✅ Settings removed: %s\n", name)
+			}
+		}
+		return nil
+	},
+}
+
+var pluginsEnableCmd = &cobra.Command{
+	Use:   "enable <name>...",
+	Short: "Enable plugins for a Discord channel",
+	Long:  "Enable installed plugins by name or filename in data/<channel>/plugins.json, so BetterDiscord loads them the next time Discord starts.",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setAddonsEnabled(cmd, betterdiscord.AddonPlugin, args, true)
+	},
+}
+
+var pluginsDisableCmd = &cobra.Command{
+	Use:   "disable <name>...",
+	Short: "Disable plugins for a Discord channel",
+	Long:  "Disable plugins by name or filename in data/<channel>/plugins.json, so BetterDiscord skips them the next time Discord starts.",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setAddonsEnabled(cmd, betterdiscord.AddonPlugin, args, false)
+	},
+}
+
+var pluginsConfigCmd = &cobra.Command{
+	Use:   "config <name> [get|set|unset] [key.path] [value]",
+	Short: "Show or edit a plugin's settings",
+	Long: `Show or edit the settings a plugin saves as <Name>.config.json in the plugins folder.
+
+Keys are dotted paths into the settings, such as "appearance.color". Values given to set are read as JSON when possible, so 5, true, and {"a": 1} keep their type; anything else is stored as a string. Restart Discord after editing so the plugin picks up the change.
+
+A plugin called Export or Import can be reached with -- before its name, or by giving an action, such as "bdcli plugins config export get".`,
+	Example: `  bdcli plugins config MyPlugin
+  bdcli plugins config MyPlugin get appearance.color
+  bdcli plugins config MyPlugin set appearance.color '"#ff0000"'
+  bdcli plugins config MyPlugin unset appearance.color
+  bdcli plugins config -- Export`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("requires a plugin name")
+		}
+		if len(args) == 1 {
+			return nil
+		}
+		switch args[1] {
+		case "get":
+			if len(args) > 3 {
+				return fmt.Errorf("get accepts at most one key")
+			}
+		case "unset":
+			if len(args) != 3 {
+				return fmt.Errorf("unset requires exactly one key")
+			}
+		case "set":
+			if len(args) != 4 {
+				return fmt.Errorf("set requires a key and a value")
+			}
+		default:
+			return fmt.Errorf("unknown action %q, expected get, set, or unset", args[1])
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPluginConfig(args)
+	},
+}
+
+// pluginConfigAction returns the arguments for 'plugins config' when a
+// subcommand of it was matched by a plugin's name, as in
+// "plugins config export get". Arguments after -- are never treated as an
+// action, so "plugins config export -- get" still exports a plugin called get.
+func pluginConfigAction(cmd *cobra.Command, args []string) ([]string, bool) {
+	if len(args) == 0 || cmd.ArgsLenAtDash() == 0 {
+		return nil, false
+	}
+	switch args[0] {
+	case "get", "set", "unset":
+		return append([]string{cmd.Name()}, args...), true
+	}
+	return nil, false
+}
+
+// runPluginConfig shows or edits a plugin's settings; args are the
+// arguments of 'plugins config'.
+func runPluginConfig(args []string) error {
+	name, err := betterdiscord.PluginConfigName(args[0])
+	if err != nil {
+		return err
+	}
+	config, err := betterdiscord.ReadPluginConfig(name)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 || (args[1] == "get" && len(args) == 2) {
+		if output.IsStructured() {
+			return output.Emit(config)
+		}
+		return writePluginConfigTable(name, config)
+	}
+
+	key := args[2]
+	switch args[1] {
+	case "get":
+		value, ok := config.Get(key)
+		if !ok {
+			return fmt.Errorf("%s is not set for plugin %s", key, name)
+		}
+		if text, isString := value.(string); isString && !output.IsStructured() {
+			fmt.Fprintln(output.Writer(), text)
+			return nil
+		}
+		return output.Emit(value)
+	case "set":
+		if err := config.Set(key, betterdiscord.ParseConfigValue(args[3])); err != nil {
+			return err
+		}
+		if err := betterdiscord.WritePluginConfig(name, config); err != nil {
+			return err
+		}
+		output.Printf("✅ Set %s for plugin '%s'\n", key, name)
+	case "unset":
+		if !config.Unset(key) {
+			return fmt.Errorf("%s is not set for plugin %s", key, name)
+		}
+		if err := betterdiscord.WritePluginConfig(name, config); err != nil {
+			return err
+		}
+		output.Printf("✅ Unset %s for plugin '%s'\n", key, name)
+	}
+	output.Println("💡 Restart Discord to apply the change")
+	return nil
+}
+
+// writePluginConfigTable prints every setting as a dotted key and its value.
+func writePluginConfigTable(name string, config betterdiscord.PluginConfig) error {
+	values := map[string]any{}
+	flattenPluginConfig("", config, values)
+	if len(values) == 0 {
+		output.Printf("📭 Plugin '%s' has no saved settings.\n", name)
+		return nil
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tw := output.NewTableWriter()
+	fmt.Fprintln(tw, "KEY\tVALUE")
+	for _, key := range keys {
+		value, isString := values[key].(string)
+		if !isString {
+			raw, err := json.Marshal(values[key])
+			if err != nil {
+				return err
+			}
+			value = string(raw)
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", key, value)
+	}
+	return tw.Flush()
+}
+
+// flattenPluginConfig adds every value below object to values, keyed by its
+// dotted path. Empty objects are kept as a value of their own.
+func flattenPluginConfig(prefix string, object map[string]any, values map[string]any) {
+	for key, value := range object {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if child, ok := value.(map[string]any); ok && len(child) > 0 {
+			flattenPluginConfig(key, child, values)
+			continue
+		}
+		values[key] = value
+	}
+}
+
+var pluginsConfigExportCmd = &cobra.Command{
+	Use:   "export [name...]",
+	Short: "Export plugin settings",
+	Long:  "Export the settings of the named plugins, or of every plugin that has saved settings, as one document keyed by plugin name. The document is printed unless --file is given.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if configArgs, ok := pluginConfigAction(cmd, args); ok {
+			return pluginsConfigCmd.Args(cmd, configArgs)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if configArgs, ok := pluginConfigAction(cmd, args); ok {
+			return runPluginConfig(configArgs)
+		}
+		fileFlag, _ := cmd.Flags().GetString("file")
+
+		var names []string
+		for _, arg := range args {
+			name, err := betterdiscord.PluginConfigName(arg)
+			if err != nil {
+				return err
+			}
+			names = append(names, name)
+		}
+
+		configs, err := betterdiscord.ExportPluginConfigs(names)
+		if err != nil {
+			return err
+		}
+		if fileFlag == "" {
+			return output.Emit(configs)
+		}
+
+		contents, err := json.MarshalIndent(configs, "", "  ")
+		if err != nil {
+			return err
+		}
+		if !dryrun.Skip(dryrun.WriteFile, fileFlag, "plugin settings export") {
+			if err := os.WriteFile(fileFlag, append(contents, '\n'), 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", fileFlag, err)
+			}
+		}
+		output.Printf("✅ Exported settings of %d plugin(s) to %s\n", len(configs), fileFlag)
+		return nil
+	},
+}
+
+var pluginsConfigImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import plugin settings",
+	Long:  "Import plugin settings written by 'bdcli plugins config export'. Each plugin in the file has its settings replaced. Use - to read from standard input.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if configArgs, ok := pluginConfigAction(cmd, args); ok {
+			return pluginsConfigCmd.Args(cmd, configArgs)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if configArgs, ok := pluginConfigAction(cmd, args); ok {
+			return runPluginConfig(configArgs)
+		}
+		var contents []byte
+		var err error
+		if args[0] == "-" {
+			contents, err = io.ReadAll(os.Stdin)
+		} else {
+			contents, err = os.ReadFile(args[0])
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", args[0], err)
+		}
+
+		configs, err := betterdiscord.DecodePluginConfigs(contents)
+		if err != nil {
+			return err
+		}
+		names, err := betterdiscord.ImportPluginConfigs(configs)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			output.Printf("✅ Imported settings for plugin '%s'\n", name)
+		}
+		output.Println("💡 Restart Discord to apply the change")
 		return nil
 	},
 }
@@ -142,10 +437,13 @@ var pluginsUpdateCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		checkOnly, _ := cmd.Flags().GetBool("check")
 		allFlag, _ := cmd.Flags().GetBool("all")
+		jobsFlag := jobsOption(cmd)
+		allowDowngrade, _ := cmd.Flags().GetBool("allow-downgrade")
 
 		// Handle --all flag
 		if allFlag {
-			return updateAllPlugins(checkOnly)
+			cmd.SilenceUsage = true
+			return updateAllPlugins(checkOnly, jobsFlag, allowDowngrade)
 		}
 
 		identifier := args[0]
@@ -164,20 +462,21 @@ var pluginsUpdateCmd = &cobra.Command{
 				localVersion := existing.Meta.Version
 				storeVersion := store.Version
 
-				if localVersion == storeVersion {
-					localName := existing.Meta.Name
-					if localName == "" {
-						localName = existing.BaseName
-					}
-					output.Printf("✅ Plugin '%s' is already up to date (v%s)\n", localName, localVersion)
-					return nil
-				}
-
 				localName := existing.Meta.Name
 				if localName == "" {
 					localName = existing.BaseName
 				}
 
+				if !betterdiscord.NeedsUpdate(localVersion, storeVersion, allowDowngrade) {
+					if semver.Compare(localVersion, storeVersion) > 0 {
+						output.Printf("✅ Plugin '%s' is newer than the store release (v%s > v%s)\n", localName, localVersion, storeVersion)
+						output.Println("💡 To replace it with the store release, use: bdcli plugins update <name|id> --allow-downgrade")
+						return nil
+					}
+					output.Printf("✅ Plugin '%s' is already up to date (v%s)\n", localName, localVersion)
+					return nil
+				}
+
 				if checkOnly {
 					output.Printf("📦 Update available for '%s'\n", localName)
 					output.Printf("   Current: v%s → Available: v%s\n", localVersion, storeVersion)
@@ -201,7 +500,52 @@ var pluginsUpdateCmd = &cobra.Command{
 	},
 }
 
-func updateAllPlugins(checkOnly bool) error {
+var pluginsHistoryCmd = &cobra.Command{
+	Use:   "history <name>",
+	Short: "List previous versions of a plugin available for rollback",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := betterdiscord.AddonHistory(betterdiscord.AddonPlugin, args[0])
+		if err != nil {
+			return err
+		}
+		if output.IsStructured() {
+			return output.Emit(entries)
+		}
+		if len(entries) == 0 {
+			output.Printf("📭 No previous versions stored for '%s'.\n", args[0])
+			return nil
+		}
+
+		tw := output.NewTableWriter()
+		fmt.Fprintln(tw, "VERSION\tSAVED")
+		for _, entry := range entries {
+			fmt.Fprintf(tw, "%s\t%s\n", output.FormatVersion(entry.Version), entry.Saved.Format(output.DateTimeFormat))
+		}
+		return tw.Flush()
+	},
+}
+
+var pluginsRollbackCmd = &cobra.Command{
+	Use:   "rollback <name>",
+	Short: "Restore a previous version of a plugin",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		versionFlag, _ := cmd.Flags().GetString("version")
+
+		entry, err := betterdiscord.RollbackAddon(betterdiscord.AddonPlugin, args[0], versionFlag)
+		if err != nil {
+			return err
+		}
+		if output.IsStructured() {
+			return output.Emit(entry)
+		}
+		output.Printf("✅ Plugin '%s' rolled back to %s\n", args[0], output.FormatVersion(entry.Version))
+		return nil
+	},
+}
+
+func updateAllPlugins(checkOnly bool, jobs int, allowDowngrade bool) error {
 	items, err := betterdiscord.ListAddons(betterdiscord.AddonPlugin)
 	if err != nil {
 		return err
@@ -212,50 +556,11 @@ func updateAllPlugins(checkOnly bool) error {
 		return nil
 	}
 
-	var toUpdate []struct {
-		entry        betterdiscord.AddonEntry
-		localVersion string
-		storeVersion string
-		name         string
-	}
-
 	output.Println("🔍 Checking for plugin updates...")
 
-	// Check each plugin for updates
-	for _, item := range items {
-		name := item.Meta.Name
-		if name == "" {
-			name = item.BaseName
-		}
-
-		// Try to find in store by name or ID
-		identifier := name
-		if identifier == "" {
-			identifier = item.BaseName
-		}
-
-		store, err := betterdiscord.FetchAddonFromStore(identifier)
-		if err != nil {
-			// Plugin not in store, skip
-			continue
-		}
-
-		localVersion := item.Meta.Version
-		storeVersion := store.Version
-
-		if localVersion != storeVersion {
-			toUpdate = append(toUpdate, struct {
-				entry        betterdiscord.AddonEntry
-				localVersion string
-				storeVersion string
-				name         string
-			}{
-				entry:        item,
-				localVersion: localVersion,
-				storeVersion: storeVersion,
-				name:         name,
-			})
-		}
+	toUpdate, err := betterdiscord.CheckAddonUpdates(betterdiscord.AddonPlugin, jobs, allowDowngrade)
+	if err != nil {
+		return err
 	}
 
 	if len(toUpdate) == 0 {
@@ -267,7 +572,7 @@ func updateAllPlugins(checkOnly bool) error {
 
 	// Show what would be updated
 	for _, item := range toUpdate {
-		output.Printf("  • %s: v%s → v%s\n", item.name, item.localVersion, item.storeVersion)
+		output.Printf("  • %s: v%s → v%s\n", item.Name(), item.Entry.Meta.Version, item.Store.Version)
 	}
 	output.Println()
 
@@ -280,22 +585,56 @@ func updateAllPlugins(checkOnly bool) error {
 	updated := 0
 	failed := 0
 
-	for _, item := range toUpdate {
-		identifier := item.name
-		if identifier == "" {
-			identifier = item.entry.BaseName
-		}
-
-		_, err := betterdiscord.UpdateAddon(betterdiscord.AddonPlugin, identifier)
-		if err != nil {
-			output.Printf("❌ Failed to update %s: %v\n", item.name, err)
+	for _, result := range betterdiscord.UpdateAddons(betterdiscord.AddonPlugin, toUpdate, jobs) {
+		if result.Err != nil {
+			output.Printf("❌ Failed to update %s: %v\n", result.Name(), result.Err)
 			failed++
 		} else {
-			output.Printf("✅ Updated %s to v%s\n", item.name, item.storeVersion)
+			output.Printf("✅ Updated %s to v%s\n", result.Name(), result.Store.Version)
 			updated++
 		}
 	}
 
 	output.Printf("\n📊 Summary: %d updated, %d failed\n", updated, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d plugin(s) could not be updated", failed)
+	}
+	return nil
+}
+
+// installedPaths lists the files an install wrote, which is several for
+// archives and GitHub releases.
+func installedPaths(resolved *betterdiscord.ResolvedAddon) []string {
+	if resolved == nil {
+		return nil
+	}
+	if len(resolved.Paths) > 0 {
+		return resolved.Paths
+	}
+	if resolved.Path == "" {
+		return nil
+	}
+	return []string{resolved.Path}
+}
+
+// setAddonsEnabled switches the addons named in args on or off for the
+// channel chosen with --channel.
+func setAddonsEnabled(cmd *cobra.Command, kind betterdiscord.AddonKind, args []string, enabled bool) error {
+	cmd.SilenceUsage = true
+	channel := models.ParseChannel(channelOption(cmd))
+	names, err := betterdiscord.GetInstallation().SetAddonsEnabled(kind, channel, args, enabled)
+	if err != nil {
+		return err
+	}
+
+	state := "disabled"
+	if enabled {
+		state = "enabled"
+	}
+	label := strings.ToUpper(string(kind[:1])) + string(kind[1:])
+	for _, name := range names {
+		output.Printf("✅ %s '%s' %s for %s\n", label, name, state, channel.Name())
+	}
+	output.Println("💡 Restart Discord to apply the change")
 	return nil
 }
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/betterdiscord/cli/internal/betterdiscord"
+	"github.com/betterdiscord/cli/internal/output"
+)
+
+func init() {
+	profileCreateCmd.Flags().StringSlice("plugin", nil, "Only include this plugin, repeat for several")
+	profileCreateCmd.Flags().StringSlice("theme", nil, "Only include this theme, repeat for several")
+	profileCreateCmd.Flags().Bool("force", false, "Replace an existing profile with the current addons")
+	profileCmd.AddCommand(profileCreateCmd)
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileUseCmd)
+	profileCmd.AddCommand(profileDeleteCmd)
+	rootCmd.AddCommand(profileCmd)
+}
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Switch between named sets of plugins and themes",
+	Long:  "Save the installed plugins and themes, and which of them are enabled for each Discord channel, as a named profile and switch between profiles with one command. Addon files that are not part of the active profile are kept in the profile store in the BetterDiscord data folder.",
+}
+
+var profileCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Save the current plugins and themes as a profile",
+	Long:  "Save the installed plugins and themes, and which of them are enabled for each Discord channel, as a profile. Pass --plugin and --theme to only include some of the installed addons, for example a minimal set for debugging; only the addons listed are included. A profile of everything becomes the active profile.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pluginFlag, _ := cmd.Flags().GetStringSlice("plugin")
+		themeFlag, _ := cmd.Flags().GetStringSlice("theme")
+		forceFlag, _ := cmd.Flags().GetBool("force")
+
+		selection := betterdiscord.ProfileSelection{Plugins: pluginFlag, Themes: themeFlag}
+		profile, err := betterdiscord.GetInstallation().CreateProfile(args[0], selection, forceFlag)
+		if err != nil {
+			return err
+		}
+		if output.IsStructured() {
+			return output.Emit(profile)
+		}
+		output.Printf("✅ Profile '%s' created with %d plugin(s) and %d theme(s)\n", args[0], len(profile.Plugins), len(profile.Themes))
+		return nil
+	},
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved profiles",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profiles, err := betterdiscord.GetInstallation().ReadProfiles()
+		if err != nil {
+			return err
+		}
+		if output.IsStructured() {
+			return output.Emit(profiles)
+		}
+		if len(profiles.Profiles) == 0 {
+			output.Println("📭 No profiles saved.")
+			output.Println("💡 To save the current addons, use: bdcli profile create <name>")
+			return nil
+		}
+
+		tw := output.NewTableWriter()
+		fmt.Fprintln(tw, "NAME\tACTIVE\tPLUGINS\tTHEMES\tCREATED")
+		for _, name := range slices.Sorted(maps.Keys(profiles.Profiles)) {
+			profile := profiles.Profiles[name]
+			active := ""
+			if name == profiles.Active {
+				active = "*"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\n", name, active, len(profile.Plugins), len(profile.Themes), profile.Created.Local().Format(output.DateTimeFormat))
+		}
+		return tw.Flush()
+	},
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Switch to a profile",
+	Long:  "Switch to a saved profile. The current plugins and themes are saved to the active profile first, so switching back restores them. Addon files that are not part of the new profile are moved to the profile store, and the addons it enabled are enabled again for each channel.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		result, err := betterdiscord.GetInstallation().UseProfile(args[0])
+		if err != nil {
+			return err
+		}
+		if output.IsStructured() {
+			return output.Emit(result)
+		}
+
+		for _, file := range result.Parked {
+			output.Printf("📦 Parked %s\n", file)
+		}
+		for _, file := range result.Restored {
+			output.Printf("♻️  Restored %s\n", file)
+		}
+		for _, file := range result.Missing {
+			output.Printf("⚠️  %s is part of the profile but was not found\n", file)
+		}
+		output.Printf("✅ Switched to profile '%s'\n", args[0])
+		output.Println("💡 Restart Discord to load the profile")
+		return nil
+	},
+}
+
+var profileDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a profile",
+	Long:  "Delete a saved profile. Addon files that are parked only for this profile are moved back into the plugins and themes folders, so nothing is lost.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		restored, err := betterdiscord.GetInstallation().DeleteProfile(args[0])
+		if err != nil {
+			return err
+		}
+		if output.IsStructured() {
+			return output.Emit(map[string]any{"profile": args[0], "restored": restored})
+		}
+
+		for _, file := range restored {
+			output.Printf("♻️  Restored %s\n", file)
+		}
+		output.Printf("✅ Profile '%s' deleted\n", args[0])
+		return nil
+	},
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/betterdiscord/cli/internal/discord"
+	"github.com/betterdiscord/cli/internal/models"
+	"github.com/betterdiscord/cli/internal/output"
+)
+
+func init() {
+	repairCmd.Flags().StringP("path", "p", "", "Path to a Discord installation")
+	repairCmd.Flags().StringP("channel", "c", "stable", "Discord release channel (stable|ptb|canary)")
+	repairCmd.Flags().BoolP("all", "a", false, "Repair all detected Discord installations")
+	repairCmd.Flags().StringP("type", "t", "", "Only consider installs of this type (native|flatpak|snap)")
+	repairCmd.Flags().Bool("redownload", false, "Download betterdiscord.asar again")
+	repairCmd.Flags().Bool("reinject", false, "Restore Discord's original index.js and inject into it again")
+	repairCmd.Flags().Bool("reset-plugins", false, "Disable every plugin for the channel by removing its plugins.json")
+	repairCmd.Flags().StringSlice("disable", nil, "Disable only these plugins for the channel, repeat for several")
+	repairCmd.Flags().Bool("restart", true, "Restart Discord after repairing")
+	rootCmd.AddCommand(repairCmd)
+}
+
+var repairCmd = &cobra.Command{
+	Use:   "repair",
+	Short: "Repairs BetterDiscord for your Discord",
+	Long: `Repair BetterDiscord for a Discord install chosen with --path or --channel (default: stable), or for every install with --all.
+
+Without any repair options, BetterDiscord is downloaded and injected again and plugins.json is removed, so every plugin is disabled. Choose --redownload, --reinject, --reset-plugins, or --disable <plugin> to only do part of that. --disable switches off just the named plugins, which is enough to recover from a plugin that crashes Discord.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pathFlag, _ := cmd.Flags().GetString("path")
+		channelFlag := channelOption(cmd)
+		allFlag, _ := cmd.Flags().GetBool("all")
+		redownloadFlag, _ := cmd.Flags().GetBool("redownload")
+		reinjectFlag, _ := cmd.Flags().GetBool("reinject")
+		resetFlag, _ := cmd.Flags().GetBool("reset-plugins")
+		disableFlag, _ := cmd.Flags().GetStringSlice("disable")
+
+		typeFlag, err := typeOption(cmd)
+		if err != nil {
+			return err
+		}
+
+		applyRestartFlag(cmd)
+
+		pathProvided := pathFlag != ""
+		channelProvided := cmd.Flags().Changed("channel")
+
+		if allFlag && (pathProvided || channelProvided) {
+			return fmt.Errorf("--all cannot be used with --path or --channel")
+		}
+
+		if pathProvided && channelProvided {
+			return fmt.Errorf("--path and --channel are mutually exclusive")
+		}
+
+		if pathProvided && typeFlag != "" {
+			return fmt.Errorf("--path and --type are mutually exclusive")
+		}
+
+		if resetFlag && len(disableFlag) > 0 {
+			return fmt.Errorf("--reset-plugins and --disable are mutually exclusive")
+		}
+
+		options := discord.RepairOptions{
+			Redownload:     redownloadFlag,
+			Reinject:       reinjectFlag,
+			ResetPlugins:   resetFlag,
+			DisablePlugins: disableFlag,
+		}
+		if !redownloadFlag && !reinjectFlag && !resetFlag && len(disableFlag) == 0 {
+			options = discord.RepairOptions{Redownload: true, Reinject: true, ResetPlugins: true}
+			output.Println("⚠️  No repair options given: plugins.json will be removed, disabling every plugin for the channel.")
+			output.Println("   Use --disable <plugin> to switch off only the broken plugin, or --redownload/--reinject to keep plugins as they are.")
+			output.Blank()
+		}
+
+		if allFlag {
+			installs := filterInstallType(getAllInstalls(), typeFlag)
+			if len(installs) == 0 {
+				return fmt.Errorf("could not find any Discord installations to repair")
+			}
+
+			if err := repairAll(installs, options); err != nil {
+				return fmt.Errorf("repair failed: %w", err)
+			}
+
+			output.Println("✅ BetterDiscord repaired for all Discord instances")
+			return nil
+		}
+
+		var install *discord.DiscordInstall
+
+		if pathProvided {
+			install = discord.ResolvePath(pathFlag)
+			if install == nil {
+				return fmt.Errorf("could not find a valid Discord installation at %s", pathFlag)
+			}
+		} else {
+			install, err = selectInstall(models.ParseChannel(channelFlag), typeFlag)
+			if err != nil {
+				return err
+			}
+			if install == nil {
+				return fmt.Errorf("could not find a valid %s installation to repair", strings.TrimSpace(channelFlag+" "+typeFlag))
+			}
+		}
+
+		if err := install.RepairBD(options); err != nil {
+			return fmt.Errorf("repair failed: %w", err)
+		}
+
+		output.Printf("✅ BetterDiscord repaired for %s\n", path.Dir(install.CorePath))
+		return nil
+	},
+}
+
+func repairAll(installs []*discord.DiscordInstall, options discord.RepairOptions) error {
+	var firstErr error
+	for _, inst := range installs {
+		output.Printf("🔧 Repairing %s %s (%s)\n", inst.Channel.Name(), inst.Version, inst.Type())
+		output.Blank()
+		if err := inst.RepairBD(options); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			output.Printf("❌ Failed to repair %s\n", path.Dir(inst.CorePath))
+			output.Printf("   %s\n", err.Error())
+			output.Blank()
+		}
+	}
+	return firstErr
+}
@@ -4,9 +4,19 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/betterdiscord/cli/internal/betterdiscord"
+	"github.com/betterdiscord/cli/internal/config"
+	"github.com/betterdiscord/cli/internal/discord"
+	"github.com/betterdiscord/cli/internal/dryrun"
+	"github.com/betterdiscord/cli/internal/models"
 	"github.com/betterdiscord/cli/internal/output"
+	"github.com/betterdiscord/cli/internal/prompt"
+	"github.com/betterdiscord/cli/internal/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -22,6 +32,7 @@ func SetVersionInfo(version, commit, date string) {
 	buildVersion = version
 	buildCommit = commit
 	buildDate = date
+	discord.CLIVersion = version
 }
 
 // GetVersion returns the semantic version string
@@ -45,31 +56,301 @@ func IsDebugBuild() bool {
 }
 
 var silent bool
+var outputFormat string
+var offline bool
+var cacheTTL time.Duration
+var bdRoot string
+var dryRun bool
+
+// settings is the config file with environment overrides applied. Commands
+// fall back to it for flags the user did not pass.
+var settings = &config.Config{}
 
 func init() {
 	rootCmd.PersistentFlags().BoolVar(&silent, "silent", false, "Suppress non-error output")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table|json|yaml)")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "Use only cached store data and make no network requests")
+	rootCmd.PersistentFlags().StringVar(&bdRoot, "bd-root", "", "Use a custom BetterDiscord root directory (contains data, plugins, and themes)")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Show what would change without changing anything")
+	rootCmd.PersistentFlags().DurationVar(&cacheTTL, "cache-ttl", betterdiscord.DefaultStoreCacheTTL, "How long cached store responses are used before revalidating")
 }
 
 var rootCmd = &cobra.Command{
 	Use:   "bdcli",
 	Short: "CLI for managing BetterDiscord",
 	Long:  `A cross-platform CLI for installing, updating, and managing BetterDiscord.`,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		if silent || isSilentEnvEnabled() {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		loaded, err := config.Load()
+		if err != nil {
+			// The config commands report the problem themselves and are how it gets fixed
+			if !isConfigCommand(cmd) {
+				return fmt.Errorf("%w (use 'bdcli config set' or 'bdcli config unset' to fix it)", err)
+			}
+			loaded = &config.Config{}
+		}
+		settings = loaded
+		dryrun.Enable(dryRun)
+
+		if !cmd.Flags().Changed("output") && settings.Output != "" {
+			outputFormat = settings.Output
+		}
+		format, err := output.ParseFormat(outputFormat)
+		if err != nil {
+			return err
+		}
+		output.SetFormat(format)
+
+		// Structured output owns stdout, so prose is always suppressed
+		if silent || isSilentEnvEnabled() || output.IsStructured() {
 			output.SetWriters(io.Discard, nil)
 		}
+		if output.IsStructured() {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+			// The plan is added to the command's document once it has finished
+			if dryrun.Enabled() {
+				output.Hold()
+			}
+		}
+
+		if err := configureEndpoints(); err != nil {
+			return err
+		}
+		if err := configureRoot(cmd); err != nil {
+			return err
+		}
+		discord.AddSearchPaths(settings.DiscordPaths...)
+		discord.AutoRestart = settings.RestartEnabled()
+		return configureStoreCache(cmd)
 	},
 	RunE: func(cmd *cobra.Command, args []string) error { return cmd.Help() },
 }
 
+// isConfigCommand reports whether cmd is config or one of its subcommands.
+func isConfigCommand(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c == configCmd {
+			return true
+		}
+	}
+	return false
+}
+
 func isSilentEnvEnabled() bool {
-	value := strings.TrimSpace(strings.ToLower(os.Getenv("BDCLI_SILENT")))
+	return isEnvEnabled("BDCLI_SILENT")
+}
+
+func isEnvEnabled(name string) bool {
+	value := strings.TrimSpace(strings.ToLower(os.Getenv(name)))
 	return value != "" && value != "0" && value != "false" && value != "no"
 }
 
+// configureEndpoints points the store and download clients at the endpoints
+// from the config file and BDCLI_*_URL environment variables.
+func configureEndpoints() error {
+	return betterdiscord.SetEndpoints(betterdiscord.Endpoints{
+		Store:    settings.Endpoints.Store,
+		Site:     settings.Endpoints.Site,
+		Releases: settings.Endpoints.Releases,
+	})
+}
+
+// configureRoot applies --bd-root, falling back to BDCLI_ROOT and the config file.
+func configureRoot(cmd *cobra.Command) error {
+	root := settings.Root
+	if cmd.Flags().Changed("bd-root") {
+		root = bdRoot
+	}
+	if root != "" {
+		absolute, err := filepath.Abs(root)
+		if err != nil {
+			return fmt.Errorf("invalid BetterDiscord root %q: %w", root, err)
+		}
+		root = absolute
+	}
+	betterdiscord.SetRoot(root)
+	return nil
+}
+
+// channelOption returns --channel, or the configured default channel when the flag was not passed.
+func channelOption(cmd *cobra.Command) string {
+	channel, _ := cmd.Flags().GetString("channel")
+	if !cmd.Flags().Changed("channel") && settings.Channel != "" {
+		return settings.Channel
+	}
+	return channel
+}
+
+// channelsOption returns every --channel given, or the configured default
+// channel when the flag was not passed.
+func channelsOption(cmd *cobra.Command) []string {
+	channels, _ := cmd.Flags().GetStringSlice("channel")
+	if !cmd.Flags().Changed("channel") && settings.Channel != "" {
+		return []string{settings.Channel}
+	}
+	return channels
+}
+
+// listChannelsOption returns the channel given with --channel, or every
+// channel that has BetterDiscord data when the flag was not passed.
+func listChannelsOption(cmd *cobra.Command) []models.DiscordChannel {
+	if cmd.Flags().Changed("channel") {
+		channel, _ := cmd.Flags().GetString("channel")
+		return []models.DiscordChannel{models.ParseChannel(channel)}
+	}
+	return betterdiscord.GetInstallation().StateChannels()
+}
+
+// writeAddonStatus prints items as a table with an enabled column for each
+// of channels.
+func writeAddonStatus(items []betterdiscord.AddonStatus, channels []models.DiscordChannel) error {
+	tw := output.NewTableWriter()
+	header := "NAME\tVERSION\tAUTHOR"
+	for _, channel := range channels {
+		header += "\t" + strings.ToUpper(channel.String())
+	}
+	fmt.Fprintln(tw, header+"\tSIZE (KB)\tMODIFIED")
+	for _, item := range items {
+		name := item.Meta.Name
+		if name == "" {
+			name = item.BaseName
+		}
+		row := name + "\t" + item.Meta.Version + "\t" + item.Meta.Author
+		for _, channel := range channels {
+			enabled := "no"
+			if item.Enabled[channel.String()] {
+				enabled = "yes"
+			}
+			row += "\t" + enabled
+		}
+		fmt.Fprintf(tw, "%s\t%.1f\t%s\n", row, float64(item.Size)/1024.0, item.Modified.Format(output.DateTimeFormat))
+	}
+	return tw.Flush()
+}
+
+// typeOption returns --type after checking it names a known install type.
+func typeOption(cmd *cobra.Command) (string, error) {
+	installType, _ := cmd.Flags().GetString("type")
+	installType = strings.ToLower(strings.TrimSpace(installType))
+	if installType != "" && !slices.Contains(discord.InstallTypes, installType) {
+		return "", fmt.Errorf("invalid install type %q (expected native, flatpak, or snap)", installType)
+	}
+	return installType, nil
+}
+
+// selectInstall picks the install of channel to act on. When several
+// installs match it asks which one, or uses the newest when nobody can
+// answer. It returns nil when no install matches.
+func selectInstall(channel models.DiscordChannel, installType string) (*discord.DiscordInstall, error) {
+	installs := discord.GetInstalls(channel, installType)
+	switch len(installs) {
+	case 0:
+		return nil, nil
+	case 1:
+		return installs[0], nil
+	}
+
+	if !prompt.IsInteractive() || silent || isSilentEnvEnabled() || output.IsStructured() {
+		output.Printf("💡 Found %d %s installations, using %s %s. Pass --type or --path to choose another.\n", len(installs), channel.Display(), installs[0].Type(), installs[0].Version)
+		return installs[0], nil
+	}
+
+	options := make([]string, len(installs))
+	for i, install := range installs {
+		options[i] = fmt.Sprintf("%s %s (%s) %s", install.Channel.Name(), install.Version, install.Type(), install.CorePath)
+	}
+	choice, err := prompt.Select(os.Stdin, output.Writer(), fmt.Sprintf("🔎 Found %d %s installations:", len(installs), channel.Display()), options)
+	if err != nil {
+		return nil, err
+	}
+	output.Blank()
+	return installs[choice], nil
+}
+
+// filterInstallType keeps the installs of installType, or all of them when it is empty.
+func filterInstallType(installs []*discord.DiscordInstall, installType string) []*discord.DiscordInstall {
+	if installType == "" {
+		return installs
+	}
+	var filtered []*discord.DiscordInstall
+	for _, install := range installs {
+		if install.Type() == installType {
+			filtered = append(filtered, install)
+		}
+	}
+	return filtered
+}
+
+// jobsOption returns --jobs, or the configured concurrency when the flag was not passed.
+func jobsOption(cmd *cobra.Command) int {
+	jobs, _ := cmd.Flags().GetInt("jobs")
+	if !cmd.Flags().Changed("jobs") && settings.Jobs > 0 {
+		return settings.Jobs
+	}
+	return jobs
+}
+
+// applyRestartFlag lets --restart override the configured restart behaviour.
+func applyRestartFlag(cmd *cobra.Command) {
+	if cmd.Flags().Changed("restart") {
+		discord.AutoRestart, _ = cmd.Flags().GetBool("restart")
+	}
+}
+
+// configureStoreCache applies --offline and --cache-ttl, falling back to
+// BDCLI_OFFLINE and BDCLI_CACHE_TTL when the flags are not given.
+func configureStoreCache(cmd *cobra.Command) error {
+	ttl := cacheTTL
+	if !cmd.Flags().Changed("cache-ttl") {
+		if value := strings.TrimSpace(os.Getenv("BDCLI_CACHE_TTL")); value != "" {
+			parsed, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("invalid BDCLI_CACHE_TTL %q: %w", value, err)
+			}
+			ttl = parsed
+		}
+	}
+	if ttl < 0 {
+		return fmt.Errorf("cache TTL must not be negative")
+	}
+
+	betterdiscord.StoreCache.TTL = ttl
+	betterdiscord.StoreCache.Offline = offline || isEnvEnabled("BDCLI_OFFLINE")
+	utils.Offline = betterdiscord.StoreCache.Offline
+	return nil
+}
+
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(output.ErrorWriter(), err)
+	err := rootCmd.Execute()
+	if dryrun.Enabled() {
+		printPlan()
+	}
+	if err != nil {
+		if output.IsStructured() {
+			output.EmitError(err) //nolint:errcheck
+		} else {
+			fmt.Fprintln(output.ErrorWriter(), err)
+		}
 		os.Exit(1)
 	}
 }
+
+// printPlan lists the actions skipped by --dry-run. In structured mode they
+// are added to the command's document as a top-level plan field.
+func printPlan() {
+	actions := dryrun.Actions()
+	if output.IsStructured() {
+		output.Release("plan", actions) //nolint:errcheck
+		return
+	}
+
+	output.Blank()
+	if len(actions) == 0 {
+		output.Println("📝 Dry run: nothing would change")
+		return
+	}
+	output.Printf("📝 Dry run: %d change(s) would be made\n", len(actions))
+	for i, action := range actions {
+		output.Printf("   %d. %s\n", i+1, action)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/betterdiscord/cli/internal/betterdiscord"
+	"github.com/betterdiscord/cli/internal/dryrun"
+	"github.com/betterdiscord/cli/internal/models"
 	"github.com/betterdiscord/cli/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -14,6 +16,7 @@ func init() {
 	storeCmd.AddCommand(storeShowCmd)
 	storeCmd.AddCommand(storePluginsCmd)
 	storeCmd.AddCommand(storeThemesCmd)
+	storeCmd.AddCommand(storeClearCacheCmd)
 
 	// Plugins subcommands
 	storePluginsCmd.AddCommand(storePluginsSearchCmd)
@@ -49,6 +52,9 @@ var storeSearchCmd = &cobra.Command{
 		}
 
 		results := betterdiscord.SearchAddons(addons, query)
+		if output.IsStructured() {
+			return emitStoreAddons(results)
+		}
 		if len(results) == 0 {
 			output.Println("📭 No addons found matching that query.")
 			return nil
@@ -75,6 +81,9 @@ var storeShowCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		if output.IsStructured() {
+			return output.Emit(addon)
+		}
 
 		betterdiscord.LogAddonInfo(addon)
 		return nil
@@ -102,6 +111,9 @@ var storePluginsSearchCmd = &cobra.Command{
 		}
 
 		results := betterdiscord.SearchAddons(addons, query)
+		if output.IsStructured() {
+			return emitStoreAddons(results)
+		}
 		if len(results) == 0 {
 			output.Println("📭 No plugins found matching that query.")
 			return nil
@@ -127,6 +139,9 @@ var storePluginsShowCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		if output.IsStructured() {
+			return output.Emit(addon)
+		}
 
 		betterdiscord.LogAddonInfo(addon)
 		return nil
@@ -154,6 +169,9 @@ var storeThemesSearchCmd = &cobra.Command{
 		}
 
 		results := betterdiscord.SearchAddons(addons, query)
+		if output.IsStructured() {
+			return emitStoreAddons(results)
+		}
 		if len(results) == 0 {
 			output.Println("📭 No themes found matching that query.")
 			return nil
@@ -179,8 +197,38 @@ var storeThemesShowCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		if output.IsStructured() {
+			return output.Emit(addon)
+		}
 
 		betterdiscord.LogAddonInfo(addon)
 		return nil
 	},
 }
+
+// ==================== Store cache ====================
+
+var storeClearCacheCmd = &cobra.Command{
+	Use:   "clear-cache",
+	Short: "Remove cached store responses",
+	Long:  "Delete the on-disk cache of store catalogues and addon entries.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryrun.Skip(dryrun.Remove, betterdiscord.StoreCache.Dir, "store cache") {
+			return nil
+		}
+		if err := betterdiscord.StoreCache.Clear(); err != nil {
+			return fmt.Errorf("failed to clear store cache: %w", err)
+		}
+		output.Println("✅ Store cache cleared")
+		return nil
+	},
+}
+
+// emitStoreAddons writes store search results as a structured document.
+func emitStoreAddons(addons []models.StoreAddon) error {
+	if addons == nil {
+		addons = []models.StoreAddon{}
+	}
+	return output.Emit(addons)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/betterdiscord/cli/internal/betterdiscord"
 	"github.com/betterdiscord/cli/internal/output"
+	"github.com/betterdiscord/cli/internal/semver"
 	"github.com/betterdiscord/cli/internal/utils"
 	"github.com/spf13/cobra"
 )
@@ -23,25 +24,19 @@ var themesListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List installed themes",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		items, err := betterdiscord.ListAddons(betterdiscord.AddonTheme)
+		channels := listChannelsOption(cmd)
+		items, err := betterdiscord.GetInstallation().ListAddonStatus(betterdiscord.AddonTheme, channels)
 		if err != nil {
 			return err
 		}
+		if output.IsStructured() {
+			return output.Emit(items)
+		}
 		if len(items) == 0 {
 			output.Println("📭 No themes installed.")
 			return nil
 		}
-
-		tw := output.NewTableWriter()
-		fmt.Fprintln(tw, "NAME\tVERSION\tAUTHOR\tSIZE (KB)\tMODIFIED")
-		for _, item := range items {
-			name := item.Meta.Name
-			if name == "" {
-				name = item.BaseName
-			}
-			fmt.Fprintf(tw, "%s\t%s\t%s\t%.1f\t%s\n", name, item.Meta.Version, item.Meta.Author, float64(item.Size)/1024.0, item.Modified.Format(output.DateTimeFormat))
-		}
-		return tw.Flush()
+		return writeAddonStatus(items, channels)
 	},
 }
 
@@ -63,13 +58,15 @@ var themesInfoCmd = &cobra.Command{
 }
 
 var themesInstallCmd = &cobra.Command{
-	Use:   "install <name|id|url>",
-	Short: "Install a theme by name, ID, or direct URL",
+	Use:   "install <name|id|url|path|github:owner/repo>",
+	Short: "Install a theme from the store, a URL, a local file, GitHub, or a .zip archive",
+	Long:  "Install a theme by store name or ID, or from a direct URL, a local file, a github:owner/repo[@ref][/path] source, or a .zip archive holding one or more themes. Without a path, github: sources install the theme files or .zip archive attached to the latest release, or to the release tagged ref. The ref cannot contain '/', and a path must name a theme file or .zip archive. Use --link to symlink a local file instead of copying it.",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		identifier := args[0]
-		// Check if not a URL and already installed
-		if !utils.IsURL(identifier) {
+		linkFlag, _ := cmd.Flags().GetBool("link")
+		// Check if not a URL, file, or GitHub source and already installed
+		if !betterdiscord.IsAddonSource(identifier) {
 			if existing := betterdiscord.FindAddon(betterdiscord.AddonTheme, identifier); existing != nil {
 				name := existing.Meta.Name
 				if name == "" {
@@ -80,12 +77,12 @@ var themesInstallCmd = &cobra.Command{
 				return nil
 			}
 		}
-		resolved, err := betterdiscord.InstallAddon(betterdiscord.AddonTheme, identifier)
-		if err != nil {
-			return err
+		resolved, err := betterdiscord.InstallAddonWith(betterdiscord.AddonTheme, identifier, betterdiscord.InstallOptions{Link: linkFlag})
+		// Files written before a failure are reported along with it
+		for _, path := range installedPaths(resolved) {
+			output.Printf("✅ Theme installed at %s\n", path)
 		}
-		output.Printf("✅ Theme installed at %s\n", resolved.Path)
-		return nil
+		return err
 	},
 }
 
@@ -102,7 +99,7 @@ var themesRemoveCmd = &cobra.Command{
 			output.Printf("❌ Theme '%s' is not installed.\n", identifier)
 			return nil
 		}
-		if err := betterdiscord.RemoveAddon(betterdiscord.AddonTheme, identifier); err != nil {
+		if err := betterdiscord.RemoveAddon(betterdiscord.AddonTheme, existing.FullFilename); err != nil {
 			return err
 		}
 		name := existing.Meta.Name
@@ -114,6 +111,26 @@ var themesRemoveCmd = &cobra.Command{
 	},
 }
 
+var themesEnableCmd = &cobra.Command{
+	Use:   "enable <name>...",
+	Short: "Enable themes for a Discord channel",
+	Long:  "Enable installed themes by name or filename in data/<channel>/themes.json, so BetterDiscord loads them the next time Discord starts.",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setAddonsEnabled(cmd, betterdiscord.AddonTheme, args, true)
+	},
+}
+
+var themesDisableCmd = &cobra.Command{
+	Use:   "disable <name>...",
+	Short: "Disable themes for a Discord channel",
+	Long:  "Disable themes by name or filename in data/<channel>/themes.json, so BetterDiscord skips them the next time Discord starts.",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setAddonsEnabled(cmd, betterdiscord.AddonTheme, args, false)
+	},
+}
+
 var themesUpdateCmd = &cobra.Command{
 	Use:   "update <name|id|url>",
 	Short: "Update a theme by name, ID, or URL",
@@ -130,10 +147,13 @@ var themesUpdateCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		checkOnly, _ := cmd.Flags().GetBool("check")
 		allFlag, _ := cmd.Flags().GetBool("all")
+		jobsFlag := jobsOption(cmd)
+		allowDowngrade, _ := cmd.Flags().GetBool("allow-downgrade")
 
 		// Handle --all flag
 		if allFlag {
-			return updateAllThemes(checkOnly)
+			cmd.SilenceUsage = true
+			return updateAllThemes(checkOnly, jobsFlag, allowDowngrade)
 		}
 
 		identifier := args[0]
@@ -152,20 +172,21 @@ var themesUpdateCmd = &cobra.Command{
 				localVersion := existing.Meta.Version
 				storeVersion := store.Version
 
-				if localVersion == storeVersion {
-					localName := existing.Meta.Name
-					if localName == "" {
-						localName = existing.BaseName
-					}
-					output.Printf("✅ Theme '%s' is already up to date (v%s)\n", localName, localVersion)
-					return nil
-				}
-
 				localName := existing.Meta.Name
 				if localName == "" {
 					localName = existing.BaseName
 				}
 
+				if !betterdiscord.NeedsUpdate(localVersion, storeVersion, allowDowngrade) {
+					if semver.Compare(localVersion, storeVersion) > 0 {
+						output.Printf("✅ Theme '%s' is newer than the store release (v%s > v%s)\n", localName, localVersion, storeVersion)
+						output.Println("💡 To replace it with the store release, use: bdcli themes update <name|id> --allow-downgrade")
+						return nil
+					}
+					output.Printf("✅ Theme '%s' is already up to date (v%s)\n", localName, localVersion)
+					return nil
+				}
+
 				if checkOnly {
 					output.Printf("📦 Update available for '%s'\n", localName)
 					output.Printf("   Current: v%s → Available: v%s\n", localVersion, storeVersion)
@@ -189,7 +210,52 @@ var themesUpdateCmd = &cobra.Command{
 	},
 }
 
-func updateAllThemes(checkOnly bool) error {
+var themesHistoryCmd = &cobra.Command{
+	Use:   "history <name>",
+	Short: "List previous versions of a theme available for rollback",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := betterdiscord.AddonHistory(betterdiscord.AddonTheme, args[0])
+		if err != nil {
+			return err
+		}
+		if output.IsStructured() {
+			return output.Emit(entries)
+		}
+		if len(entries) == 0 {
+			output.Printf("📭 No previous versions stored for '%s'.\n", args[0])
+			return nil
+		}
+
+		tw := output.NewTableWriter()
+		fmt.Fprintln(tw, "VERSION\tSAVED")
+		for _, entry := range entries {
+			fmt.Fprintf(tw, "%s\t%s\n", output.FormatVersion(entry.Version), entry.Saved.Format(output.DateTimeFormat))
+		}
+		return tw.Flush()
+	},
+}
+
+var themesRollbackCmd = &cobra.Command{
+	Use:   "rollback <name>",
+	Short: "Restore a previous version of a theme",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		versionFlag, _ := cmd.Flags().GetString("version")
+
+		entry, err := betterdiscord.RollbackAddon(betterdiscord.AddonTheme, args[0], versionFlag)
+		if err != nil {
+			return err
+		}
+		if output.IsStructured() {
+			return output.Emit(entry)
+		}
+		output.Printf("✅ Theme '%s' rolled back to %s\n", args[0], output.FormatVersion(entry.Version))
+		return nil
+	},
+}
+
+func updateAllThemes(checkOnly bool, jobs int, allowDowngrade bool) error {
 	items, err := betterdiscord.ListAddons(betterdiscord.AddonTheme)
 	if err != nil {
 		return err
@@ -200,50 +266,11 @@ func updateAllThemes(checkOnly bool) error {
 		return nil
 	}
 
-	var toUpdate []struct {
-		entry        betterdiscord.AddonEntry
-		localVersion string
-		storeVersion string
-		name         string
-	}
-
 	output.Println("🔍 Checking for theme updates...")
 
-	// Check each theme for updates
-	for _, item := range items {
-		name := item.Meta.Name
-		if name == "" {
-			name = item.BaseName
-		}
-
-		// Try to find in store by name or ID
-		identifier := name
-		if identifier == "" {
-			identifier = item.BaseName
-		}
-
-		store, err := betterdiscord.FetchAddonFromStore(identifier)
-		if err != nil {
-			// Theme not in store, skip
-			continue
-		}
-
-		localVersion := item.Meta.Version
-		storeVersion := store.Version
-
-		if localVersion != storeVersion {
-			toUpdate = append(toUpdate, struct {
-				entry        betterdiscord.AddonEntry
-				localVersion string
-				storeVersion string
-				name         string
-			}{
-				entry:        item,
-				localVersion: localVersion,
-				storeVersion: storeVersion,
-				name:         name,
-			})
-		}
+	toUpdate, err := betterdiscord.CheckAddonUpdates(betterdiscord.AddonTheme, jobs, allowDowngrade)
+	if err != nil {
+		return err
 	}
 
 	if len(toUpdate) == 0 {
@@ -255,7 +282,7 @@ func updateAllThemes(checkOnly bool) error {
 
 	// Show what would be updated
 	for _, item := range toUpdate {
-		output.Printf("  • %s: v%s → v%s\n", item.name, item.localVersion, item.storeVersion)
+		output.Printf("  • %s: v%s → v%s\n", item.Name(), item.Entry.Meta.Version, item.Store.Version)
 	}
 	output.Println()
 
@@ -268,23 +295,20 @@ func updateAllThemes(checkOnly bool) error {
 	updated := 0
 	failed := 0
 
-	for _, item := range toUpdate {
-		identifier := item.name
-		if identifier == "" {
-			identifier = item.entry.BaseName
-		}
-
-		_, err := betterdiscord.UpdateAddon(betterdiscord.AddonTheme, identifier)
-		if err != nil {
-			output.Printf("❌ Failed to update %s: %v\n", item.name, err)
+	for _, result := range betterdiscord.UpdateAddons(betterdiscord.AddonTheme, toUpdate, jobs) {
+		if result.Err != nil {
+			output.Printf("❌ Failed to update %s: %v\n", result.Name(), result.Err)
 			failed++
 		} else {
-			output.Printf("✅ Updated %s to v%s\n", item.name, item.storeVersion)
+			output.Printf("✅ Updated %s to v%s\n", result.Name(), result.Store.Version)
 			updated++
 		}
 	}
 
 	output.Printf("\n📊 Summary: %d updated, %d failed\n", updated, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d theme(s) could not be updated", failed)
+	}
 	return nil
 }
 
@@ -294,8 +318,19 @@ func initThemesCmd() {
 	themesCmd.AddCommand(themesInfoCmd)
 	themesCmd.AddCommand(themesInstallCmd)
 	themesCmd.AddCommand(themesRemoveCmd)
+	themesCmd.AddCommand(themesEnableCmd)
+	themesCmd.AddCommand(themesDisableCmd)
 	themesCmd.AddCommand(themesUpdateCmd)
+	themesCmd.AddCommand(themesHistoryCmd)
+	themesCmd.AddCommand(themesRollbackCmd)
 	rootCmd.AddCommand(themesCmd)
+	themesListCmd.Flags().StringP("channel", "c", "", "Only show whether themes are enabled for this Discord release channel (stable|ptb|canary)")
+	themesInstallCmd.Flags().Bool("link", false, "Symlink a local theme file instead of copying it")
+	themesEnableCmd.Flags().StringP("channel", "c", "stable", "Discord release channel (stable|ptb|canary)")
+	themesDisableCmd.Flags().StringP("channel", "c", "stable", "Discord release channel (stable|ptb|canary)")
 	themesUpdateCmd.Flags().BoolP("check", "c", false, "Check for available updates without installing")
 	themesUpdateCmd.Flags().BoolP("all", "a", false, "Update all installed themes")
+	themesUpdateCmd.Flags().Bool("allow-downgrade", false, "Replace addons that are newer than the store release")
+	themesUpdateCmd.Flags().IntP("jobs", "j", 4, "Number of concurrent checks and downloads when using --all")
+	themesRollbackCmd.Flags().String("version", "", "Version to restore (default: most recent previous version)")
 }
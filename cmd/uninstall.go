@@ -2,8 +2,12 @@ package cmd
 
 import (
 	"fmt"
+	"maps"
 	"path"
+	"slices"
+	"strings"
 
+	"github.com/betterdiscord/cli/internal/backup"
 	"github.com/betterdiscord/cli/internal/betterdiscord"
 	"github.com/betterdiscord/cli/internal/discord"
 	"github.com/betterdiscord/cli/internal/models"
@@ -14,8 +18,11 @@ import (
 func init() {
 	uninstallCmd.Flags().StringP("path", "p", "", "Path to a Discord installation")
 	uninstallCmd.Flags().StringP("channel", "c", "stable", "Discord release channel (stable|ptb|canary)")
+	uninstallCmd.Flags().Bool("restart", true, "Restart Discord after uninstalling")
 	uninstallCmd.Flags().BoolP("full", "f", false, "Fully uninstall BetterDiscord (uninjects all instances and removes all BetterDiscord folders)")
+	uninstallCmd.Flags().Bool("no-backup", false, "Skip the automatic backup taken before --full removes the BetterDiscord folders")
 	uninstallCmd.Flags().BoolP("all", "a", false, "Uninject BetterDiscord from all detected Discord installations")
+	uninstallCmd.Flags().StringP("type", "t", "", "Only consider installs of this type (native|flatpak|snap)")
 	rootCmd.AddCommand(uninstallCmd)
 }
 
@@ -25,10 +32,17 @@ var uninstallCmd = &cobra.Command{
 	Long:  "Uninstall BetterDiscord by specifying --path/--channel for a single install, --all to uninject from all installs, or --full for complete removal.",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		pathFlag, _ := cmd.Flags().GetString("path")
-		channelFlag, _ := cmd.Flags().GetString("channel")
+		channelFlag := channelOption(cmd)
 		fullFlag, _ := cmd.Flags().GetBool("full")
 		allFlag, _ := cmd.Flags().GetBool("all")
 
+		typeFlag, err := typeOption(cmd)
+		if err != nil {
+			return err
+		}
+
+		applyRestartFlag(cmd)
+
 		pathProvided := pathFlag != ""
 		channelProvided := cmd.Flags().Changed("channel")
 
@@ -45,10 +59,20 @@ var uninstallCmd = &cobra.Command{
 			return fmt.Errorf("--path and --channel are mutually exclusive")
 		}
 
+		if typeFlag != "" && (fullFlag || pathProvided) {
+			return fmt.Errorf("--type cannot be used with --full or --path")
+		}
+
 		// Full uninstall: all installs, delete all BD folders
 		if fullFlag {
 			installs := getAllInstalls()
 
+			for _, bd := range betterDiscordRoots(installs) {
+				if err := autoBackup(cmd, bd, backup.ReasonUninstall); err != nil {
+					return err
+				}
+			}
+
 			if err := uninstallAll(installs); err != nil {
 				return fmt.Errorf("uninstallation failed: %w", err)
 			}
@@ -63,7 +87,7 @@ var uninstallCmd = &cobra.Command{
 
 		// Uninject all: all installs, no deletion
 		if allFlag {
-			installs := getAllInstalls()
+			installs := filterInstallType(getAllInstalls(), typeFlag)
 
 			if err := uninstallAll(installs); err != nil {
 				return fmt.Errorf("uninstallation failed: %w", err)
@@ -82,11 +106,12 @@ var uninstallCmd = &cobra.Command{
 				return fmt.Errorf("could not find a valid Discord installation at %s", pathFlag)
 			}
 		} else {
-			channel := models.ParseChannel(channelFlag)
-			resolvedPath := discord.GetSuggestedPath(channel)
-			install = discord.ResolvePath(resolvedPath)
+			install, err = selectInstall(models.ParseChannel(channelFlag), typeFlag)
+			if err != nil {
+				return err
+			}
 			if install == nil {
-				return fmt.Errorf("could not find a valid %s installation to uninstall", channelFlag)
+				return fmt.Errorf("could not find a valid %s installation to uninstall", strings.TrimSpace(channelFlag+" "+typeFlag))
 			}
 		}
 
@@ -110,8 +135,8 @@ func getAllInstalls() []*discord.DiscordInstall {
 	// And also the chance of actually having duplicates is pretty much zero, but this is just in case
 	// If you are reading this and you do have duplicates, please tell me because that would be very interesting and I would like to know how that happened
 	// If you are reading this and confused by these comments, hi, I'm Zerebos, the author of this code, and I just wanted to have a little fun here, hope you don't mind
-	for _, list := range installsMap {
-		for _, inst := range list {
+	for _, channel := range models.Channels {
+		for _, inst := range installsMap[channel] {
 			if inst == nil {
 				continue
 			}
@@ -141,6 +166,21 @@ func uninstallAll(installs []*discord.DiscordInstall) error {
 }
 
 func removeAllBetterDiscord(installs []*discord.DiscordInstall) error {
+	var firstErr error
+	for _, bd := range betterDiscordRoots(installs) {
+		if err := bd.RemoveAll(); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return firstErr
+}
+
+// betterDiscordRoots returns the BetterDiscord folders used by installs,
+// sorted by path.
+func betterDiscordRoots(installs []*discord.DiscordInstall) []*betterdiscord.BDInstall {
 	roots := map[string]*betterdiscord.BDInstall{}
 
 	// This is actually a case where duplicates will happen, because
@@ -155,14 +195,9 @@ func removeAllBetterDiscord(installs []*discord.DiscordInstall) error {
 		roots[bd.Root()] = bd
 	}
 
-	var firstErr error
-	for _, bd := range roots {
-		if err := bd.RemoveAll(); err != nil {
-			if firstErr == nil {
-				firstErr = err
-			}
-		}
+	var sorted []*betterdiscord.BDInstall
+	for _, key := range slices.Sorted(maps.Keys(roots)) {
+		sorted = append(sorted, roots[key])
 	}
-
-	return firstErr
+	return sorted
 }
@@ -5,14 +5,17 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/betterdiscord/cli/internal/backup"
 	"github.com/betterdiscord/cli/internal/betterdiscord"
-	"github.com/betterdiscord/cli/internal/models"
 	"github.com/betterdiscord/cli/internal/output"
-	"github.com/betterdiscord/cli/internal/utils"
+	"github.com/betterdiscord/cli/internal/semver"
 )
 
 func init() {
 	updateCmd.Flags().BoolP("check", "c", false, "Only check for updates, don't install")
+	updateCmd.Flags().Bool("allow-downgrade", false, "Install the latest release even if the installed version is newer")
+	updateCmd.Flags().Bool("no-backup", false, "Skip the automatic backup taken before updating")
+	updateCmd.Flags().String("expect-sha256", "", "Abort unless the downloaded betterdiscord.asar has this SHA-256 digest")
 	rootCmd.AddCommand(updateCmd)
 }
 
@@ -28,6 +31,11 @@ var updateCmd = &cobra.Command{
 		}
 
 		checkFlag, _ := cmd.Flags().GetBool("check")
+		expectFlag, _ := cmd.Flags().GetString("expect-sha256")
+		allowDowngrade, _ := cmd.Flags().GetBool("allow-downgrade")
+		if err := bdinstall.SetExpectedSHA256(expectFlag); err != nil {
+			return err
+		}
 
 		// Get current version
 		buildinfo, err := bdinstall.ReadBuildinfo()
@@ -39,7 +47,7 @@ var updateCmd = &cobra.Command{
 		output.Printf("📦 Current version: %s\n", output.FormatVersion(currentVersion))
 
 		// Get latest release from GitHub
-		release, err := utils.DownloadJSON[models.GitHubRelease]("https://api.github.com/repos/BetterDiscord/BetterDiscord/releases/latest")
+		release, err := betterdiscord.FetchLatestRelease()
 		if err != nil {
 			return fmt.Errorf("failed to check for updates: %w", err)
 		}
@@ -47,17 +55,40 @@ var updateCmd = &cobra.Command{
 		latestVersion := release.TagName
 		output.Printf("🌐 Latest version:  %s\n\n", output.FormatVersion(latestVersion))
 
+		comparison := semver.Compare(currentVersion, latestVersion)
+		doc := updateDocument{
+			Current:         output.FormatVersion(currentVersion),
+			Latest:          output.FormatVersion(latestVersion),
+			UpdateAvailable: comparison < 0 || (comparison > 0 && allowDowngrade),
+		}
+
 		// Check if update is needed
-		if compareVersions(currentVersion, latestVersion) >= 0 {
-			output.Printf("✅ You are already on the latest version!\n")
-			return nil
+		if !doc.UpdateAvailable {
+			if comparison > 0 {
+				output.Printf("✅ Installed version is newer than the latest release (use --allow-downgrade to replace it)\n")
+			} else {
+				output.Printf("✅ You are already on the latest version!\n")
+			}
+			return emitUpdateDocument(doc)
 		}
 
-		output.Printf("🎉 New version available!\n\n")
+		if comparison > 0 {
+			output.Printf("⬇️  Downgrading to the latest release\n\n")
+		} else {
+			output.Printf("🎉 New version available!\n\n")
+		}
 
 		if checkFlag {
-			output.Println("Run 'bdcli update' to install the update")
-			return nil
+			if comparison > 0 {
+				output.Println("Run 'bdcli update --allow-downgrade' to install the latest release")
+			} else {
+				output.Println("Run 'bdcli update' to install the update")
+			}
+			return emitUpdateDocument(doc)
+		}
+
+		if err := autoBackup(cmd, bdinstall, backup.ReasonUpdate); err != nil {
+			return err
 		}
 
 		// Download the latest version
@@ -65,71 +96,28 @@ var updateCmd = &cobra.Command{
 		if err := bdinstall.Download(); err != nil {
 			return fmt.Errorf("failed to download update: %w", err)
 		}
+		doc.Updated = true
 
 		output.Printf("✅ Successfully updated to %s\n\n", output.FormatVersion(latestVersion))
 
 		bdinstall.LogBuildinfo()
 
 		output.Println("\n🔄 Please restart Discord for the update to take effect.")
-		return nil
+		return emitUpdateDocument(doc)
 	},
 }
 
-// compareVersions compares two semantic versions (e.g., "1.0.156" vs "1.0.157")
-// Returns -1 if v1 < v2, 0 if equal, 1 if v1 > v2
-func compareVersions(v1, v2 string) int {
-	// Strip 'v' prefix if present
-
-	if len(v1) > 0 && v1[0] == 'v' {
-		v1 = v1[1:]
-	}
-	if len(v2) > 0 && v2[0] == 'v' {
-		v2 = v2[1:]
-	}
-
-	// Parse into version parts
-	parts1 := splitVersion(v1)
-	parts2 := splitVersion(v2)
-
-	// Compare each part
-	maxLen := max(len(parts2), len(parts1))
-
-	for i := range maxLen {
-		var p1, p2 int
-
-		if i < len(parts1) {
-			fmt.Sscanf(parts1[i], "%d", &p1)
-		}
-		if i < len(parts2) {
-			fmt.Sscanf(parts2[i], "%d", &p2)
-		}
-
-		if p1 < p2 {
-			return -1
-		} else if p1 > p2 {
-			return 1
-		}
-	}
-
-	return 0
+// updateDocument is the structured result of an update check.
+type updateDocument struct {
+	Current         string `json:"current"`
+	Latest          string `json:"latest"`
+	UpdateAvailable bool   `json:"updateAvailable"`
+	Updated         bool   `json:"updated"`
 }
 
-// splitVersion splits a version string into parts (e.g., "1.0.156" -> ["1", "0", "156"])
-func splitVersion(v string) []string {
-	var parts []string
-	var current string
-	for i := 0; i < len(v); i++ {
-		if v[i] == '.' {
-			if current != "" {
-				parts = append(parts, current)
-				current = ""
-			}
-		} else if v[i] >= '0' && v[i] <= '9' {
-			current += string(v[i])
-		}
-	}
-	if current != "" {
-		parts = append(parts, current)
+func emitUpdateDocument(doc updateDocument) error {
+	if !output.IsStructured() {
+		return nil
 	}
-	return parts
+	return output.Emit(doc)
 }
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/betterdiscord/cli/internal/discord"
+	"github.com/betterdiscord/cli/internal/output"
+)
+
+func init() {
+	watchCmd.Flags().Bool("restart", true, "Restart Discord after re-injecting")
+	watchCmd.Flags().Duration("delay", discord.DefaultWatchDelay, "How long to wait for Discord to finish updating before re-injecting")
+	rootCmd.AddCommand(watchCmd)
+}
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Re-inject BetterDiscord when Discord updates",
+	Long:  "Watch the Discord install folders and re-inject BetterDiscord whenever a Discord update creates a new core folder. Only installs that are currently injected are watched. Runs until interrupted.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		delayFlag, _ := cmd.Flags().GetDuration("delay")
+		applyRestartFlag(cmd)
+
+		watcher := discord.NewWatcher(discord.SearchPaths())
+		watcher.Restart = discord.AutoRestart
+		watcher.Delay = delayFlag
+		watcher.OnEvent = logWatchEvent
+
+		installs := watcher.Installs()
+		if len(installs) == 0 {
+			return fmt.Errorf("no injected Discord installations to watch, run 'bdcli install' first")
+		}
+
+		output.Println("👀 Watching for Discord updates:")
+		for _, install := range installs {
+			output.Printf("   %s %s (%s)\n", install.Channel.Name(), install.Version, install.CorePath)
+		}
+		output.Println("   Press Ctrl+C to stop.")
+		output.Blank()
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if err := watcher.Run(ctx); err != nil {
+			return fmt.Errorf("watching failed: %w", err)
+		}
+		output.Println("👋 Stopped watching")
+		return nil
+	},
+}
+
+// logWatchEvent prints a watcher event as a log line, or as a document per
+// event with structured output.
+func logWatchEvent(event discord.WatchEvent) {
+	if output.IsStructured() {
+		output.Emit(event) //nolint:errcheck
+		return
+	}
+
+	stamp := event.Time.Format(time.DateTime)
+	switch {
+	case event.Action == discord.WatchError:
+		output.Printf("[%s] ⚠️  Watch error, still watching: %s\n", stamp, event.Error)
+	case event.Action == discord.WatchFailed:
+		output.Printf("[%s] ❌ Failed to re-inject %s %s: %s\n", stamp, event.Channel.Name(), event.Version, event.Error)
+	case event.Error != "":
+		output.Printf("[%s] ⚠️  Re-injected %s %s but could not restart it: %s\n", stamp, event.Channel.Name(), event.Version, event.Error)
+	case event.Restarted:
+		output.Printf("[%s] ✅ Re-injected and restarted %s %s\n", stamp, event.Channel.Name(), event.Version)
+	default:
+		output.Printf("[%s] ✅ Re-injected %s %s, restart Discord to load BetterDiscord\n", stamp, event.Channel.Name(), event.Version)
+	}
+}
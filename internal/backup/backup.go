@@ -0,0 +1,655 @@
+// Package backup archives the BetterDiscord folder to a tar.gz file with a
+// manifest of its contents and restores it again.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/betterdiscord/cli/internal/betterdiscord"
+	"github.com/betterdiscord/cli/internal/config"
+	"github.com/betterdiscord/cli/internal/dryrun"
+)
+
+// ManifestName is the first entry of every backup archive.
+const ManifestName = "manifest.json"
+
+// manifestFormat is bumped when the manifest changes incompatibly.
+const manifestFormat = 1
+
+// MaxAutomatic is how many automatic backups are kept before the oldest
+// are removed.
+const MaxAutomatic = 10
+
+// Reasons a backup was taken.
+const (
+	ReasonManual    = "manual"
+	ReasonUpdate    = "update"
+	ReasonUninstall = "uninstall"
+	ReasonRestore   = "restore"
+)
+
+// Parts of the BetterDiscord folder that can be restored on their own.
+const (
+	Plugins  = "plugins"
+	Themes   = "themes"
+	Settings = "settings"
+)
+
+// Parts lists everything --only accepts.
+var Parts = []string{Plugins, Themes, Settings}
+
+// Manifest describes what a backup contains.
+type Manifest struct {
+	Format    int                     `json:"format"`
+	Created   time.Time               `json:"created"`
+	Reason    string                  `json:"reason"`
+	Root      string                  `json:"root"`
+	Buildinfo betterdiscord.Buildinfo `json:"buildinfo"`
+	Plugins   []Addon                 `json:"plugins"`
+	Themes    []Addon                 `json:"themes"`
+	Files     []File                  `json:"files"`
+}
+
+// Addon is an addon that was installed when the backup was taken.
+type Addon struct {
+	Name     string `json:"name"`
+	Version  string `json:"version,omitempty"`
+	Filename string `json:"filename"`
+}
+
+// File is a file in the backup, relative to the BetterDiscord folder.
+type File struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Info is a backup found in the backup folder.
+type Info struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+	Manifest
+}
+
+// Dir returns the folder automatic backups are kept in, next to the CLI's
+// config file.
+func Dir() (string, error) {
+	configPath, err := config.Path()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), "backups"), nil
+}
+
+// DefaultPath returns a new file name in the backup folder for a backup
+// taken for reason.
+func DefaultPath(reason string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fmt.Sprintf("%s-%s.tar.gz", reason, time.Now().Format("20060102-150405"))), nil
+}
+
+// Create archives the BetterDiscord folder of bd to dest.
+func Create(bd *betterdiscord.BDInstall, dest, reason string) (*Manifest, error) {
+	manifest, err := newManifest(bd, dest, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	if dryrun.Skip(dryrun.WriteFile, dest, "backup of "+bd.Root()) {
+		return manifest, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return nil, err
+	}
+
+	tmp := dest + ".tmp"
+	if err := writeArchive(bd.Root(), tmp, manifest); err != nil {
+		os.Remove(tmp) //nolint:errcheck
+		return nil, err
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		os.Remove(tmp) //nolint:errcheck
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// Auto takes an automatic backup of bd into the backup folder and removes
+// the oldest automatic backups beyond MaxAutomatic, except those in keep. It
+// returns an empty path when there is no BetterDiscord folder to back up.
+func Auto(bd *betterdiscord.BDInstall, reason string, keep ...string) (string, error) {
+	if _, err := os.Stat(bd.Root()); os.IsNotExist(err) {
+		return "", nil
+	}
+
+	dest, err := DefaultPath(reason)
+	if err != nil {
+		return "", err
+	}
+	if _, err := Create(bd, dest, reason); err != nil {
+		return "", err
+	}
+	return dest, prune(keep)
+}
+
+// List returns the backups in the backup folder, newest first. Files that
+// are not readable backups are skipped.
+func List() ([]Info, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []Info
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".tar.gz") {
+			continue
+		}
+		full := filepath.Join(dir, e.Name())
+		manifest, err := ReadManifest(full)
+		if err != nil {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Info{Path: full, Size: info.Size(), Manifest: *manifest})
+	}
+
+	slices.SortStableFunc(backups, func(a, b Info) int {
+		return b.Created.Compare(a.Created)
+	})
+	return backups, nil
+}
+
+// ReadManifest reads the manifest of the backup at file.
+func ReadManifest(file string) (*Manifest, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() //nolint:errcheck
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a backup: %w", file, err)
+	}
+	return readManifest(tar.NewReader(gz))
+}
+
+// Restore verifies the backup at file against its manifest and writes its
+// files into the BetterDiscord folder of bd. When only is not empty, just
+// those parts are restored. Files that are not in the backup are left alone.
+// Nothing is replaced until every file has been extracted, and if placing a
+// file fails the files placed before it are put back. It returns the
+// manifest and the restored paths.
+func Restore(bd *betterdiscord.BDInstall, file string, only []string) (*Manifest, []string, error) {
+	for _, part := range only {
+		if !slices.Contains(Parts, part) {
+			return nil, nil, fmt.Errorf("unknown backup part %q, expected one of %s", part, strings.Join(Parts, ", "))
+		}
+	}
+
+	manifest, err := Verify(file)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Every file is extracted next to its destination before any is
+	// replaced, so a failure partway leaves the folder as it was
+	type staged struct {
+		name, tmp, dest string
+		kept            bool
+	}
+	var files []staged
+	discard := func() {
+		for _, f := range files {
+			os.Remove(f.tmp) //nolint:errcheck
+		}
+	}
+
+	var restored []string
+	err = walkArchive(file, func(header *tar.Header, r io.Reader) error {
+		if !selected(header.Name, only) {
+			return nil
+		}
+		dest := filepath.Join(bd.Root(), filepath.FromSlash(header.Name))
+		if dryrun.Skip(dryrun.WriteFile, dest, "from backup") {
+			restored = append(restored, header.Name)
+			return nil
+		}
+		tmp, err := stageFile(dest, r, header.FileInfo().Mode().Perm())
+		if err != nil {
+			return fmt.Errorf("failed to restore %s: %w", header.Name, err)
+		}
+		files = append(files, staged{name: header.Name, tmp: tmp, dest: dest})
+		return nil
+	})
+	if err != nil {
+		discard()
+		return nil, nil, err
+	}
+
+	// Each existing file is kept aside until every file is in place, so a
+	// failed rename can put back the files placed before it
+	for i := range files {
+		f := &files[i]
+		if err := placeFile(f.tmp, f.dest, &f.kept); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				unplaceFile(files[j].dest, files[j].kept)
+			}
+			files = files[i:]
+			discard()
+			return nil, nil, fmt.Errorf("failed to restore %s: %w", f.name, err)
+		}
+		restored = append(restored, f.name)
+	}
+	for _, f := range files {
+		if f.kept {
+			os.Remove(f.dest + keptSuffix) //nolint:errcheck
+		}
+	}
+	return manifest, restored, nil
+}
+
+// keptSuffix is added to a file replaced by Restore until the restore is done.
+const keptSuffix = ".restore-old"
+
+// placeFile moves tmp to dest. An existing dest is first moved to
+// dest+keptSuffix and kept is set; it is moved back if the rename fails.
+func placeFile(tmp, dest string, kept *bool) error {
+	if _, err := os.Lstat(dest); err == nil {
+		if err := os.Rename(dest, dest+keptSuffix); err != nil {
+			return err
+		}
+		*kept = true
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		if *kept {
+			os.Rename(dest+keptSuffix, dest) //nolint:errcheck
+			*kept = false
+		}
+		return err
+	}
+	return nil
+}
+
+// unplaceFile undoes placeFile: dest is put back as it was, or removed when
+// there was nothing there before.
+func unplaceFile(dest string, kept bool) {
+	if kept {
+		os.Rename(dest+keptSuffix, dest) //nolint:errcheck
+		return
+	}
+	os.Remove(dest) //nolint:errcheck
+}
+
+// Verify checks that every file in the backup at file matches its manifest
+// and that no file is missing, and returns the manifest.
+func Verify(file string) (*Manifest, error) {
+	manifest, err := ReadManifest(file)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Format > manifestFormat {
+		return nil, fmt.Errorf("backup format %d is newer than this version of bdcli supports", manifest.Format)
+	}
+
+	expected := make(map[string]File, len(manifest.Files))
+	for _, f := range manifest.Files {
+		expected[f.Path] = f
+	}
+
+	seen := map[string]bool{}
+	err = walkArchive(file, func(header *tar.Header, r io.Reader) error {
+		want, ok := expected[header.Name]
+		if !ok {
+			return fmt.Errorf("backup is corrupted: %s is not in the manifest", header.Name)
+		}
+		hash := sha256.New()
+		size, err := io.Copy(hash, r)
+		if err != nil {
+			return err
+		}
+		if size != want.Size || hex.EncodeToString(hash.Sum(nil)) != want.SHA256 {
+			return fmt.Errorf("backup is corrupted: %s does not match the manifest", header.Name)
+		}
+		seen[header.Name] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range manifest.Files {
+		if !seen[f.Path] {
+			return nil, fmt.Errorf("backup is corrupted: %s is missing", f.Path)
+		}
+	}
+	return manifest, nil
+}
+
+// Part returns which part of the BetterDiscord folder the archive path name
+// belongs to. betterdiscord.asar belongs to none and is only restored with
+// everything else.
+func Part(name string) string {
+	switch {
+	case strings.HasPrefix(name, "plugins/"):
+		return Plugins
+	case strings.HasPrefix(name, "themes/"):
+		return Themes
+	case name == "data/betterdiscord.asar":
+		return ""
+	default:
+		return Settings
+	}
+}
+
+func selected(name string, only []string) bool {
+	return len(only) == 0 || slices.Contains(only, Part(name))
+}
+
+func newManifest(bd *betterdiscord.BDInstall, dest, reason string) (*Manifest, error) {
+	root := bd.Root()
+	if _, err := os.Stat(root); err != nil {
+		return nil, fmt.Errorf("nothing to back up: %w", err)
+	}
+
+	manifest := &Manifest{
+		Format:    manifestFormat,
+		Created:   time.Now().UTC(),
+		Reason:    reason,
+		Root:      root,
+		Buildinfo: betterdiscord.NewBuildinfo(),
+		Plugins:   []Addon{},
+		Themes:    []Addon{},
+		Files:     []File{},
+	}
+	if buildinfo, err := bd.ReadBuildinfo(); err == nil {
+		manifest.Buildinfo = buildinfo
+	}
+
+	for _, kind := range []betterdiscord.AddonKind{betterdiscord.AddonPlugin, betterdiscord.AddonTheme} {
+		items, err := bd.ListAddons(kind)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		addons := manifestAddons(items)
+		if kind == betterdiscord.AddonPlugin {
+			manifest.Plugins = addons
+		} else {
+			manifest.Themes = addons
+		}
+	}
+
+	skip, _ := filepath.Abs(dest)
+	err := filepath.WalkDir(root, func(full string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() && !linksToFile(full, d) {
+			return nil
+		}
+		if abs, _ := filepath.Abs(full); abs == skip || abs == skip+".tmp" {
+			return nil
+		}
+		rel, err := filepath.Rel(root, full)
+		if err != nil {
+			return err
+		}
+		size, digest, err := hashFile(full)
+		if err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, File{Path: filepath.ToSlash(rel), Size: size, SHA256: digest})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func manifestAddons(items []betterdiscord.AddonEntry) []Addon {
+	addons := make([]Addon, 0, len(items))
+	for _, item := range items {
+		name := item.Meta.Name
+		if name == "" {
+			name = item.BaseName
+		}
+		addons = append(addons, Addon{Name: name, Version: item.Meta.Version, Filename: item.FullFilename})
+	}
+	return addons
+}
+
+func writeArchive(root, dest string, manifest *Manifest) (err error) {
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	contents, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	header := &tar.Header{Name: ManifestName, Mode: 0644, Size: int64(len(contents)), ModTime: manifest.Created}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	if _, err := tw.Write(contents); err != nil {
+		return err
+	}
+
+	for _, file := range manifest.Files {
+		if err := addFile(tw, root, file); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func addFile(tw *tar.Writer, root string, file File) error {
+	src, err := os.Open(filepath.Join(root, filepath.FromSlash(file.Path)))
+	if err != nil {
+		return err
+	}
+	defer src.Close() //nolint:errcheck
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() != file.Size {
+		return fmt.Errorf("%s changed while the backup was being taken", file.Path)
+	}
+
+	header := &tar.Header{Name: file.Path, Mode: int64(info.Mode().Perm()), Size: file.Size, ModTime: info.ModTime()}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, src)
+	return err
+}
+
+func readManifest(tr *tar.Reader) (*Manifest, error) {
+	header, err := tr.Next()
+	if err != nil || header.Name != ManifestName {
+		return nil, fmt.Errorf("backup has no manifest")
+	}
+
+	manifest := &Manifest{}
+	if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+		return nil, fmt.Errorf("invalid backup manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// walkArchive calls fn for every file in the backup after the manifest,
+// rejecting entries that would land outside the BetterDiscord folder.
+func walkArchive(file string, fn func(header *tar.Header, r io.Reader) error) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("%s is not a backup: %w", file, err)
+	}
+	tr := tar.NewReader(gz)
+	if _, err := readManifest(tr); err != nil {
+		return err
+	}
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read backup: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if !safeName(header.Name) {
+			return fmt.Errorf("backup contains an unsafe path: %s", header.Name)
+		}
+		if err := fn(header, tr); err != nil {
+			return err
+		}
+	}
+}
+
+func safeName(name string) bool {
+	clean := path.Clean(name)
+	return clean == name && !path.IsAbs(clean) && clean != ".." && !strings.HasPrefix(clean, "../") && !strings.Contains(name, `\`)
+}
+
+// linksToFile reports whether d is a symlink to a regular file, such as a
+// plugin linked in from a development folder. Its contents are backed up.
+func linksToFile(full string, d fs.DirEntry) bool {
+	if d.Type()&fs.ModeSymlink == 0 {
+		return false
+	}
+	info, err := os.Stat(full)
+	return err == nil && info.Mode().IsRegular()
+}
+
+func hashFile(file string) (int64, string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close() //nolint:errcheck
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// stageFile writes r to a temporary file next to dest and returns its path,
+// so it can be renamed over dest in one step. Renaming also replaces a
+// symlink at dest instead of writing through it.
+func stageFile(dest string, r io.Reader, perm fs.FileMode) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(filepath.Dir(dest), "."+filepath.Base(dest)+".*.restore")
+	if err != nil {
+		return "", err
+	}
+	tmp := f.Name()
+	_, err = io.Copy(f, r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(tmp, perm)
+	}
+	if err != nil {
+		os.Remove(tmp) //nolint:errcheck
+		return "", err
+	}
+	return tmp, nil
+}
+
+// prune removes automatic backups beyond MaxAutomatic, oldest first, never
+// touching the backups in keep.
+func prune(keep []string) error {
+	backups, err := List()
+	if err != nil {
+		return err
+	}
+
+	kept := 0
+	for _, info := range backups {
+		if info.Reason == ReasonManual {
+			continue
+		}
+		if slices.ContainsFunc(keep, func(path string) bool { return sameFile(path, info.Path) }) {
+			continue
+		}
+		kept++
+		if kept <= MaxAutomatic {
+			continue
+		}
+		if dryrun.Skip(dryrun.Remove, info.Path, "old automatic backup") {
+			continue
+		}
+		if err := os.Remove(info.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sameFile(a, b string) bool {
+	ai, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bi, err := os.Stat(b)
+	return err == nil && os.SameFile(ai, bi)
+}
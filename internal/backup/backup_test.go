@@ -0,0 +1,342 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/betterdiscord/cli/internal/betterdiscord"
+)
+
+func setupRoot(t *testing.T) *betterdiscord.BDInstall {
+	t.Helper()
+	t.Setenv("BDCLI_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	bd := betterdiscord.New(t.TempDir())
+
+	files := map[string]string{
+		"data/betterdiscord.asar":    `version:"1.9.3"`,
+		"data/stable/plugins.json":   `{"Crashy": true}`,
+		"plugins/Crashy.plugin.js":   "/**\n * @name Crashy\n * @version 1.0.0\n */\n",
+		"plugins/Crashy.config.json": `{"size": 3}`,
+		"themes/Dark.theme.css":      "/**\n * @name Dark\n * @version 2.0.0\n */\n",
+	}
+	for name, contents := range files {
+		full := filepath.Join(bd.Root(), filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(full), 0755)      //nolint:errcheck
+		os.WriteFile(full, []byte(contents), 0644) //nolint:errcheck
+	}
+	return bd
+}
+
+func readRootFile(t *testing.T, bd *betterdiscord.BDInstall, name string) string {
+	t.Helper()
+	contents, err := os.ReadFile(filepath.Join(bd.Root(), filepath.FromSlash(name)))
+	if err != nil {
+		return ""
+	}
+	return string(contents)
+}
+
+func TestCreate_Manifest(t *testing.T) {
+	bd := setupRoot(t)
+	dest := filepath.Join(t.TempDir(), "backup.tar.gz")
+
+	manifest, err := Create(bd, dest, ReasonManual)
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	if manifest.Buildinfo.Version != "1.9.3" {
+		t.Errorf("Buildinfo.Version = %s, expected 1.9.3", manifest.Buildinfo.Version)
+	}
+	if len(manifest.Plugins) != 1 || manifest.Plugins[0].Name != "Crashy" || manifest.Plugins[0].Version != "1.0.0" {
+		t.Errorf("Plugins = %v, expected Crashy 1.0.0", manifest.Plugins)
+	}
+	if len(manifest.Themes) != 1 || manifest.Themes[0].Name != "Dark" {
+		t.Errorf("Themes = %v, expected Dark", manifest.Themes)
+	}
+	if len(manifest.Files) != 5 {
+		t.Errorf("Files = %d, expected 5", len(manifest.Files))
+	}
+
+	read, err := ReadManifest(dest)
+	if err != nil {
+		t.Fatalf("ReadManifest() failed: %v", err)
+	}
+	if read.Reason != ReasonManual || len(read.Files) != len(manifest.Files) {
+		t.Errorf("ReadManifest() = %+v, expected the written manifest", read)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	tests := []struct {
+		name     string
+		only     []string
+		restored map[string]bool
+	}{
+		{"everything", nil, map[string]bool{"plugins": true, "themes": true, "settings": true}},
+		{"plugins", []string{Plugins}, map[string]bool{"plugins": true}},
+		{"themes and settings", []string{Themes, Settings}, map[string]bool{"themes": true, "settings": true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bd := setupRoot(t)
+			dest := filepath.Join(t.TempDir(), "backup.tar.gz")
+			if _, err := Create(bd, dest, ReasonManual); err != nil {
+				t.Fatalf("Create() failed: %v", err)
+			}
+
+			os.WriteFile(filepath.Join(bd.Plugins(), "Crashy.config.json"), []byte(`{"size": 9}`), 0644) //nolint:errcheck
+			os.Remove(filepath.Join(bd.Themes(), "Dark.theme.css"))                                      //nolint:errcheck
+			os.WriteFile(filepath.Join(bd.Data(), "stable", "plugins.json"), []byte(`{}`), 0644)         //nolint:errcheck
+
+			if _, _, err := Restore(bd, dest, tt.only); err != nil {
+				t.Fatalf("Restore() failed: %v", err)
+			}
+
+			checks := map[string]struct {
+				file     string
+				restored string
+			}{
+				"plugins":  {"plugins/Crashy.config.json", `{"size": 3}`},
+				"themes":   {"themes/Dark.theme.css", "/**\n * @name Dark\n * @version 2.0.0\n */\n"},
+				"settings": {"data/stable/plugins.json", `{"Crashy": true}`},
+			}
+			for part, check := range checks {
+				got := readRootFile(t, bd, check.file)
+				if (got == check.restored) != tt.restored[part] {
+					t.Errorf("%s restored = %v, expected %v (contents %q)", part, got == check.restored, tt.restored[part], got)
+				}
+			}
+		})
+	}
+}
+
+func TestRestore_FailureKeepsFiles(t *testing.T) {
+	bd := setupRoot(t)
+	dest := filepath.Join(t.TempDir(), "backup.tar.gz")
+	if _, err := Create(bd, dest, ReasonManual); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	os.WriteFile(filepath.Join(bd.Plugins(), "Crashy.config.json"), []byte(`{"size": 9}`), 0644) //nolint:errcheck
+	// The themes folder cannot be created, so restoring the theme fails
+	os.RemoveAll(bd.Themes())                    //nolint:errcheck
+	os.WriteFile(bd.Themes(), []byte("x"), 0644) //nolint:errcheck
+
+	if _, _, err := Restore(bd, dest, nil); err == nil {
+		t.Fatal("Restore() should fail when a file cannot be written")
+	}
+	if got := readRootFile(t, bd, "plugins/Crashy.config.json"); got != `{"size": 9}` {
+		t.Errorf("Crashy.config.json = %q, expected it untouched after a failed restore", got)
+	}
+	entries, _ := os.ReadDir(bd.Plugins())
+	if len(entries) != 2 {
+		t.Errorf("failed restore left %d files in the plugins folder, expected 2", len(entries))
+	}
+}
+
+func TestRestore_RenameFailurePutsBackFiles(t *testing.T) {
+	bd := setupRoot(t)
+	dest := filepath.Join(t.TempDir(), "backup.tar.gz")
+	if _, err := Create(bd, dest, ReasonManual); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	os.WriteFile(filepath.Join(bd.Plugins(), "Crashy.config.json"), []byte(`{"size": 9}`), 0644) //nolint:errcheck
+	os.Remove(filepath.Join(bd.Themes(), "Dark.theme.css"))                                      //nolint:errcheck
+	// The settings file cannot be kept aside, so placing it fails after the
+	// plugins and themes are already in place
+	settings := filepath.Join(bd.Data(), "stable", "plugins.json")
+	os.MkdirAll(filepath.Join(settings+".restore-old", "blocked"), 0755) //nolint:errcheck
+
+	if _, _, err := Restore(bd, dest, nil); err == nil {
+		t.Fatal("Restore() should fail when a file cannot be placed")
+	}
+	if got := readRootFile(t, bd, "plugins/Crashy.config.json"); got != `{"size": 9}` {
+		t.Errorf("Crashy.config.json = %q, expected it put back after a failed restore", got)
+	}
+	if _, err := os.Stat(filepath.Join(bd.Themes(), "Dark.theme.css")); !os.IsNotExist(err) {
+		t.Error("Dark.theme.css should be removed again after a failed restore")
+	}
+	if got := readRootFile(t, bd, "data/stable/plugins.json"); got != `{"Crashy": true}` {
+		t.Errorf("plugins.json = %q, expected it untouched", got)
+	}
+	for _, dir := range []string{bd.Plugins(), bd.Themes()} {
+		entries, _ := os.ReadDir(dir)
+		for _, e := range entries {
+			if strings.HasSuffix(e.Name(), ".restore-old") || strings.HasSuffix(e.Name(), ".restore") {
+				t.Errorf("failed restore left %s behind", e.Name())
+			}
+		}
+	}
+}
+
+func TestRestore_UnknownPart(t *testing.T) {
+	bd := setupRoot(t)
+	if _, _, err := Restore(bd, "missing.tar.gz", []string{"everything"}); err == nil {
+		t.Error("Restore() should reject unknown parts")
+	}
+}
+
+// writeTestArchive writes an archive with the given manifest and entries.
+func writeTestArchive(t *testing.T, manifest string, entries map[string]string) string {
+	t.Helper()
+	dest := filepath.Join(t.TempDir(), "backup.tar.gz")
+	f, err := os.Create(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	write := func(name, contents string) {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents))}) //nolint:errcheck
+		tw.Write([]byte(contents))                                                      //nolint:errcheck
+	}
+	write(ManifestName, manifest)
+	for name, contents := range entries {
+		write(name, contents)
+	}
+	tw.Close() //nolint:errcheck
+	gz.Close() //nolint:errcheck
+	f.Close()  //nolint:errcheck
+	return dest
+}
+
+func TestVerify_Rejects(t *testing.T) {
+	// sha256 of "hello"
+	const hello = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	manifest := func(name string) string {
+		return fmt.Sprintf(`{"format": 1, "files": [{"path": %q, "size": 5, "sha256": %q}]}`, name, hello)
+	}
+
+	tests := []struct {
+		name     string
+		manifest string
+		entries  map[string]string
+		expected string
+	}{
+		{"valid", manifest("plugins/a.plugin.js"), map[string]string{"plugins/a.plugin.js": "hello"}, ""},
+		{"modified", manifest("plugins/a.plugin.js"), map[string]string{"plugins/a.plugin.js": "HELLO"}, "does not match"},
+		{"missing", manifest("plugins/a.plugin.js"), nil, "is missing"},
+		{"extra", manifest("plugins/a.plugin.js"), map[string]string{"plugins/a.plugin.js": "hello", "plugins/b.plugin.js": "hello"}, "not in the manifest"},
+		{"traversal", manifest("../evil.js"), map[string]string{"../evil.js": "hello"}, "unsafe path"},
+		{"newer format", `{"format": 99}`, nil, "newer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Verify(writeTestArchive(t, tt.manifest, tt.entries))
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("Verify() failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Verify() error = %v, expected it to mention %q", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPart(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"plugins/a.plugin.js", Plugins},
+		{"plugins/a.config.json", Plugins},
+		{"themes/b.theme.css", Themes},
+		{"data/stable/plugins.json", Settings},
+		{"data/betterdiscord.asar", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Part(tt.name); got != tt.expected {
+			t.Errorf("Part(%q) = %q, expected %q", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestAuto_Prunes(t *testing.T) {
+	bd := setupRoot(t)
+	dir, err := Dir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Create(bd, filepath.Join(dir, "manual.tar.gz"), ReasonManual); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	for i := range MaxAutomatic + 2 {
+		if _, err := Create(bd, filepath.Join(dir, fmt.Sprintf("update-%02d.tar.gz", i)), ReasonUpdate); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+	}
+
+	dest, err := Auto(bd, ReasonUninstall)
+	if err != nil || dest == "" {
+		t.Fatalf("Auto() = %q, %v", dest, err)
+	}
+
+	backups, err := List()
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	if len(backups) != MaxAutomatic+1 {
+		t.Fatalf("List() returned %d backups, expected %d", len(backups), MaxAutomatic+1)
+	}
+	if backups[0].Path != dest {
+		t.Errorf("newest backup = %s, expected %s", backups[0].Path, dest)
+	}
+	for _, name := range []string{"manual.tar.gz", "update-03.tar.gz"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s should be kept", name)
+		}
+	}
+	for _, name := range []string{"update-00.tar.gz", "update-01.tar.gz", "update-02.tar.gz"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			t.Errorf("%s should have been pruned", name)
+		}
+	}
+}
+
+func TestAuto_Keeps(t *testing.T) {
+	bd := setupRoot(t)
+	dir, err := Dir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range MaxAutomatic + 1 {
+		if _, err := Create(bd, filepath.Join(dir, fmt.Sprintf("update-%02d.tar.gz", i)), ReasonUpdate); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+	}
+
+	oldest := filepath.Join(dir, "update-00.tar.gz")
+	if _, err := Auto(bd, ReasonRestore, oldest); err != nil {
+		t.Fatalf("Auto() failed: %v", err)
+	}
+	if _, err := os.Stat(oldest); err != nil {
+		t.Error("the backup being restored should not be pruned")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "update-01.tar.gz")); err == nil {
+		t.Error("update-01.tar.gz should have been pruned instead")
+	}
+}
+
+func TestAuto_NoRoot(t *testing.T) {
+	t.Setenv("BDCLI_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	bd := betterdiscord.New(filepath.Join(t.TempDir(), "missing"))
+
+	dest, err := Auto(bd, ReasonUpdate)
+	if err != nil || dest != "" {
+		t.Errorf("Auto() = %q, %v, expected nothing to be backed up", dest, err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/betterdiscord/cli/internal/dryrun"
 	"github.com/betterdiscord/cli/internal/models"
 	"github.com/betterdiscord/cli/internal/output"
 	"github.com/betterdiscord/cli/internal/utils"
@@ -36,13 +37,24 @@ type AddonEntry struct {
 type ResolvedAddon struct {
 	Store *models.StoreAddon // Metadata from store (nil if not found)
 	Path  string             // Local install path
+	Paths []string           // Every installed file, when the source held several
 }
 
 // ListAddons returns the locally installed addons for the given kind.
 func ListAddons(kind AddonKind) ([]AddonEntry, error) {
-	dir, err := addonDir(kind)
-	if err != nil {
-		return nil, err
+	return GetInstallation().ListAddons(kind)
+}
+
+// ListAddons returns the addons of the given kind in this BetterDiscord folder.
+func (i *BDInstall) ListAddons(kind AddonKind) ([]AddonEntry, error) {
+	var dir string
+	switch kind {
+	case AddonPlugin:
+		dir = i.plugins
+	case AddonTheme:
+		dir = i.themes
+	default:
+		return nil, fmt.Errorf("unknown addon kind: %s", kind)
 	}
 
 	entries, err := os.ReadDir(dir)
@@ -86,7 +98,12 @@ func FindAddon(kind AddonKind, identifier string) *AddonEntry {
 	if err != nil {
 		return nil
 	}
+	return matchAddon(items, identifier)
+}
 
+// matchAddon returns the addon in items matching identifier by filename,
+// base name, or meta name, or nil.
+func matchAddon(items []AddonEntry, identifier string) *AddonEntry {
 	lower := strings.ToLower(identifier)
 	for i := range items {
 		// Match by filename (case-insensitive)
@@ -136,15 +153,9 @@ func InstallAddon(kind AddonKind, identifier string) (*ResolvedAddon, error) {
 
 	resolved.Store = addon
 
-	// Use the latest_source_url from the store if available
-	downloadURL := addon.LatestSourceURL
-	if downloadURL == "" {
-		// Fallback: use the download redirect URL
-		url, err := GetAddonDownloadURL(addon.ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to resolve download URL: %w", err)
-		}
-		downloadURL = url
+	downloadURL, err := resolveDownloadURL(addon)
+	if err != nil {
+		return nil, err
 	}
 
 	dest, err := downloadAddon(kind, dir, downloadURL)
@@ -168,6 +179,9 @@ func RemoveAddon(kind AddonKind, identifier string) error {
 	for _, name := range candidates {
 		full := filepath.Join(dir, name)
 		if _, statErr := os.Stat(full); statErr == nil {
+			if dryrun.Skip(dryrun.Remove, full, "") {
+				return nil
+			}
 			return os.Remove(full)
 		}
 	}
@@ -175,13 +189,151 @@ func RemoveAddon(kind AddonKind, identifier string) error {
 	return fmt.Errorf("addon %s not found", identifier)
 }
 
-// UpdateAddon removes then installs again, returning resolved metadata.
+// UpdateAddon downloads the latest version of an addon and swaps it in for the
+// installed file. The download is validated before anything is replaced, the
+// previous file is kept as a .bak generation, and it is restored on failure.
 func UpdateAddon(kind AddonKind, identifier string) (*ResolvedAddon, error) {
-	err := RemoveAddon(kind, identifier)
+	dir, err := addonDir(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := &ResolvedAddon{}
+	downloadURL := identifier
+
+	var existing *AddonEntry
+	if !utils.IsURL(identifier) {
+		existing = FindAddon(kind, identifier)
+		if existing == nil {
+			return nil, fmt.Errorf("addon %s not found", identifier)
+		}
+
+		addon, err := FetchAddonFromStore(identifier)
+		if err != nil {
+			return nil, fmt.Errorf("addon not found: %w", err)
+		}
+		resolved.Store = addon
+
+		downloadURL, err = resolveDownloadURL(addon)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Keep the installed filename so the swap replaces the same file
+	dest := ""
+	if existing != nil {
+		dest = existing.Path
+	} else {
+		dest, err = addonDestination(kind, dir, downloadURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := swapAddon(kind, dest, downloadURL); err != nil {
+		return nil, err
+	}
+
+	resolved.Path = dest
+	if resolved.Store != nil {
+		LogAddonInfo(resolved.Store)
+	}
+	return resolved, nil
+}
+
+// UpdateInstalledAddon updates an installed addon from already fetched store
+// metadata, without any additional store lookups or logging.
+func UpdateInstalledAddon(kind AddonKind, entry *AddonEntry, addon *models.StoreAddon) (*ResolvedAddon, error) {
+	downloadURL, err := resolveDownloadURL(addon)
 	if err != nil {
 		return nil, err
 	}
-	return InstallAddon(kind, identifier)
+
+	if err := swapAddon(kind, entry.Path, downloadURL); err != nil {
+		return nil, err
+	}
+
+	return &ResolvedAddon{Store: addon, Path: entry.Path}, nil
+}
+
+// swapAddon replaces dest with the addon at downloadURL and records the
+// replaced version in the addon's history.
+func swapAddon(kind AddonKind, dest, downloadURL string) error {
+	if dryrun.Skip(dryrun.Download, dest, "replace from "+downloadURL) {
+		return nil
+	}
+
+	hadPrevious := utils.Exists(dest)
+	if err := replaceAddon(dest, downloadURL); err != nil {
+		return err
+	}
+
+	// Keep the replaced version around for rollback
+	if hadPrevious {
+		recordReplaced(kind, dest)
+	}
+
+	return nil
+}
+
+// recordReplaced saves dest.bak, the version dest just replaced, in the
+// addon's history.
+func recordReplaced(kind AddonKind, dest string) {
+	dir, err := historyDir(kind, filepath.Base(dest))
+	if err == nil {
+		err = recordHistory(dir, dest+".bak")
+	}
+	if err != nil {
+		output.Printf("⚠️  Unable to save previous version to history: %s\n", err.Error())
+	}
+}
+
+// replaceAddon downloads rawURL and swaps it in for dest. The download must
+// parse as an addon with a name and version. The previous file is kept as
+// dest.bak, and dest is left untouched if the download or validation fails.
+func replaceAddon(dest, rawURL string) error {
+	hadPrevious := utils.Exists(dest)
+
+	_, err := utils.DownloadFileVerified(rawURL, dest, func(tmpPath string) error {
+		if err := validateAddonFile(tmpPath); err != nil {
+			return err
+		}
+		if hadPrevious {
+			return utils.CopyFile(dest, dest+".bak")
+		}
+		return nil
+	})
+	return err
+}
+
+// validateAddonFile ensures path holds an addon with at least a name and version.
+func validateAddonFile(path string) error {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	meta := parseJSDoc(string(contents))
+	if meta.Name == "" || meta.Version == "" {
+		return fmt.Errorf("downloaded file is not a valid addon (missing @name or @version)")
+	}
+	return nil
+}
+
+// resolveDownloadURL picks the download URL for a store addon.
+func resolveDownloadURL(addon *models.StoreAddon) (string, error) {
+	// Use the latest_source_url from the store if available
+	if addon.LatestSourceURL != "" {
+		return addon.LatestSourceURL, nil
+	}
+
+	// Fallback: use the download redirect URL
+	url, err := GetAddonDownloadURL(addon.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve download URL: %w", err)
+	}
+	return url, nil
 }
 
 func addonDir(kind AddonKind) (string, error) {
@@ -198,6 +350,24 @@ func addonDir(kind AddonKind) (string, error) {
 }
 
 func downloadAddon(kind AddonKind, dir, rawURL string) (string, error) {
+	dest, err := addonDestination(kind, dir, rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	if dryrun.Skip(dryrun.Download, dest, "from "+rawURL) {
+		return dest, nil
+	}
+
+	if _, err := utils.DownloadFile(rawURL, dest); err != nil {
+		return "", err
+	}
+	return dest, nil
+}
+
+// addonDestination derives the local path for an addon downloaded from rawURL,
+// guaranteeing it stays inside dir.
+func addonDestination(kind AddonKind, dir, rawURL string) (string, error) {
 	parsed, err := url.Parse(rawURL)
 	if err != nil {
 		return "", err
@@ -206,7 +376,13 @@ func downloadAddon(kind AddonKind, dir, rawURL string) (string, error) {
 	if base == "." || base == "/" || base == "" {
 		base = fmt.Sprintf("addon_%d", time.Now().Unix())
 	}
+	return addonPath(kind, dir, base)
+}
 
+// addonPath returns where the addon file base is installed in dir, adding
+// the extension for kind when it is missing and guaranteeing the path stays
+// inside dir.
+func addonPath(kind AddonKind, dir, base string) (string, error) {
 	// Ensure correct extension for type when missing
 	if !isAddonFile(kind, base) {
 		switch kind {
@@ -228,9 +404,6 @@ func downloadAddon(kind AddonKind, dir, rawURL string) (string, error) {
 		return "", fmt.Errorf("resolved addon path is outside the addon directory")
 	}
 
-	if _, err := utils.DownloadFile(rawURL, dest); err != nil {
-		return "", err
-	}
 	return dest, nil
 }
 
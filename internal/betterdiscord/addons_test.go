@@ -0,0 +1,130 @@
+package betterdiscord
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const validPlugin = `/**
+ * @name Example
+ * @version 2.0.0
+ */
+module.exports = class Example {};
+`
+
+func newAddonServer(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body)) //nolint:errcheck
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestReplaceAddon(t *testing.T) {
+	server := newAddonServer(t, http.StatusOK, validPlugin)
+
+	dest := filepath.Join(t.TempDir(), "Example.plugin.js")
+	os.WriteFile(dest, []byte("old version"), 0644) //nolint:errcheck
+
+	if err := replaceAddon(dest, server.URL); err != nil {
+		t.Fatalf("replaceAddon() failed: %v", err)
+	}
+
+	contents, _ := os.ReadFile(dest)
+	if string(contents) != validPlugin {
+		t.Errorf("addon was not replaced, got %q", string(contents))
+	}
+
+	backup, err := os.ReadFile(dest + ".bak")
+	if err != nil {
+		t.Fatalf("backup was not created: %v", err)
+	}
+	if string(backup) != "old version" {
+		t.Errorf("backup = %q, expected previous contents", string(backup))
+	}
+}
+
+func TestReplaceAddon_InvalidDownload(t *testing.T) {
+	server := newAddonServer(t, http.StatusOK, "<html>not an addon</html>")
+
+	dest := filepath.Join(t.TempDir(), "Example.plugin.js")
+	os.WriteFile(dest, []byte("old version"), 0644) //nolint:errcheck
+
+	if err := replaceAddon(dest, server.URL); err == nil {
+		t.Fatal("replaceAddon() should reject a download without @name/@version")
+	}
+
+	contents, _ := os.ReadFile(dest)
+	if string(contents) != "old version" {
+		t.Errorf("addon was modified on invalid download, got %q", string(contents))
+	}
+	if _, err := os.Stat(dest + ".bak"); !os.IsNotExist(err) {
+		t.Error("backup should not be created when validation fails")
+	}
+}
+
+func TestReplaceAddon_FailedDownload(t *testing.T) {
+	server := newAddonServer(t, http.StatusNotFound, "")
+
+	dest := filepath.Join(t.TempDir(), "Example.plugin.js")
+	os.WriteFile(dest, []byte("old version"), 0644) //nolint:errcheck
+
+	if err := replaceAddon(dest, server.URL); err == nil {
+		t.Fatal("replaceAddon() should fail on a bad status")
+	}
+
+	contents, _ := os.ReadFile(dest)
+	if string(contents) != "old version" {
+		t.Errorf("addon was modified on failed download, got %q", string(contents))
+	}
+}
+
+func TestValidateAddonFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		wantErr  bool
+	}{
+		{"Valid addon", validPlugin, false},
+		{"Missing version", "/**\n * @name Example\n */", true},
+		{"Missing name", "/**\n * @version 1.0.0\n */", true},
+		{"No meta", "console.log('hi');", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "test.plugin.js")
+			os.WriteFile(path, []byte(tt.contents), 0644) //nolint:errcheck
+
+			err := validateAddonFile(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateAddonFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAddonDestination_PathTraversal(t *testing.T) {
+	dir := t.TempDir()
+
+	dest, err := addonDestination(AddonPlugin, dir, "https://example.com/files/Example.plugin.js")
+	if err != nil {
+		t.Fatalf("addonDestination() failed: %v", err)
+	}
+	if dest != filepath.Join(dir, "Example.plugin.js") {
+		t.Errorf("addonDestination() = %s", dest)
+	}
+
+	dest, err = addonDestination(AddonTheme, dir, "https://example.com/files/Example")
+	if err != nil {
+		t.Fatalf("addonDestination() failed: %v", err)
+	}
+	if dest != filepath.Join(dir, "Example.theme.css") {
+		t.Errorf("addonDestination() should append the theme extension, got %s", dest)
+	}
+}
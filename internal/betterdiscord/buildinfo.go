@@ -11,10 +11,10 @@ import (
 )
 
 type Buildinfo struct {
-	Version string
-	Commit  string
-	Branch  string
-	Mode    string
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+	Branch  string `json:"branch"`
+	Mode    string `json:"mode"`
 }
 
 func NewBuildinfo() Buildinfo {
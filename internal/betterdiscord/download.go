@@ -1,20 +1,45 @@
 package betterdiscord
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 
+	"github.com/betterdiscord/cli/internal/dryrun"
 	"github.com/betterdiscord/cli/internal/models"
 	"github.com/betterdiscord/cli/internal/output"
 	"github.com/betterdiscord/cli/internal/utils"
 )
 
+// checksumAssetNames are the release assets checked for a published digest
+// when the asset itself does not carry one.
+var checksumAssetNames = []string{"checksums.txt", "SHA256SUMS", "betterdiscord.asar.sha256"}
+
+// errNoPublishedDigest is returned when a download has nothing to be
+// verified against.
+var errNoPublishedDigest = errors.New("no checksum is published for betterdiscord.asar; pass --expect-sha256 to verify the download yourself")
+
 func (i *BDInstall) download() error {
 	if i.hasDownloaded {
 		output.Printf("✅ Already downloaded to %s\n", i.asar)
 		return nil
 	}
 
-	resp, err := utils.DownloadFile("https://betterdiscord.app/Download/betterdiscord.asar", i.asar)
+	if dryrun.Skip(dryrun.Download, i.asar, "from "+asarDownloadURL()) {
+		i.hasDownloaded = true
+		return nil
+	}
+
+	// The website does not publish checksums, so its download is checked
+	// against the GitHub release of the version it reports
+	resp, err := utils.DownloadFileChecked(asarDownloadURL(), i.asar, func(resp *http.Response, tmpPath string) error {
+		published, err := releaseDigest(resp.Header.Get("x-bd-version"))
+		if err != nil && i.expectedSHA256 == "" {
+			return err
+		}
+		return i.verify(tmpPath, published)
+	})
 	if err == nil {
 		version := resp.Header.Get("x-bd-version")
 		if version == "" {
@@ -32,21 +57,14 @@ func (i *BDInstall) download() error {
 	}
 
 	// Get download URL from GitHub API
-	apiData, err := utils.DownloadJSON[models.GitHubRelease]("https://api.github.com/repos/BetterDiscord/BetterDiscord/releases/latest")
+	apiData, err := FetchLatestRelease()
 	if err != nil {
 		output.Println("❌ Failed to get asset url from GitHub")
 		output.Printf("❌ %s\n", err.Error())
 		return err
 	}
 
-	var index = -1
-	for idx, asset := range apiData.Assets {
-		if asset.Name == "betterdiscord.asar" {
-			index = idx
-			break
-		}
-	}
-
+	index := asarAssetIndex(apiData)
 	if index == -1 {
 		output.Println("❌ Failed to find the BetterDiscord asar on GitHub")
 		return fmt.Errorf("failed to find betterdiscord.asar asset in GitHub release")
@@ -59,8 +77,14 @@ func (i *BDInstall) download() error {
 		output.Printf("✅ Found BetterDiscord: %s\n", downloadUrl)
 	}
 
+	published := publishedDigest(apiData, index)
+	if published == "" && i.expectedSHA256 == "" {
+		output.Println("❌ GitHub release does not publish a checksum for betterdiscord.asar")
+		return errNoPublishedDigest
+	}
+
 	// Download asar into the BD folder
-	_, err = utils.DownloadFile(downloadUrl, i.asar)
+	_, err = i.downloadVerified(downloadUrl, published)
 	if err != nil {
 		output.Println("❌ Failed to download BetterDiscord from GitHub")
 		output.Printf("❌ %s\n", err.Error())
@@ -76,3 +100,111 @@ func (i *BDInstall) download() error {
 
 	return nil
 }
+
+// downloadVerified downloads url over the asar, but only once the download
+// matches both the published and the user-expected digest. On any failure
+// the existing asar is left untouched.
+func (i *BDInstall) downloadVerified(url, published string) (*http.Response, error) {
+	return utils.DownloadFileVerified(url, i.asar, func(tmpPath string) error {
+		return i.verify(tmpPath, published)
+	})
+}
+
+func (i *BDInstall) verify(path, published string) error {
+	if published == "" && i.expectedSHA256 == "" {
+		return errNoPublishedDigest
+	}
+
+	actual, err := utils.SHA256File(path)
+	if err != nil {
+		return err
+	}
+
+	if published != "" && actual != utils.NormalizeSHA256(published) {
+		return fmt.Errorf("checksum mismatch: published %s, downloaded %s", utils.NormalizeSHA256(published), actual)
+	}
+	if i.expectedSHA256 != "" && actual != i.expectedSHA256 {
+		return fmt.Errorf("checksum mismatch: expected %s, downloaded %s", i.expectedSHA256, actual)
+	}
+
+	output.Printf("✅ Verified SHA-256 %s\n", actual)
+	return nil
+}
+
+// releaseDigest returns the SHA-256 published with the GitHub release of
+// version for betterdiscord.asar.
+func releaseDigest(version string) (string, error) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if version == "" {
+		return "", fmt.Errorf("the website did not report which version it served, so it cannot be verified")
+	}
+
+	release, err := FetchRelease("v" + version)
+	if err != nil {
+		return "", fmt.Errorf("failed to find the release of %s to verify against: %w", output.FormatVersion(version), err)
+	}
+	index := asarAssetIndex(release)
+	if index == -1 {
+		return "", fmt.Errorf("release %s has no betterdiscord.asar to verify against", output.FormatVersion(version))
+	}
+	digest := publishedDigest(release, index)
+	if digest == "" {
+		return "", fmt.Errorf("release %s does not publish a checksum for betterdiscord.asar", output.FormatVersion(version))
+	}
+	return digest, nil
+}
+
+// asarAssetIndex returns the index of betterdiscord.asar in the assets of
+// release, or -1 if it has none.
+func asarAssetIndex(release *models.GitHubRelease) int {
+	for idx, asset := range release.Assets {
+		if asset.Name == "betterdiscord.asar" {
+			return idx
+		}
+	}
+	return -1
+}
+
+// publishedDigest returns the SHA-256 published for the asset at index,
+// preferring the digest GitHub attaches to the asset and falling back to
+// a checksums file in the same release.
+func publishedDigest(release *models.GitHubRelease, index int) string {
+	asset := release.Assets[index]
+	if strings.HasPrefix(strings.ToLower(asset.Digest), "sha256:") {
+		return utils.NormalizeSHA256(asset.Digest)
+	}
+
+	for _, candidate := range release.Assets {
+		for _, name := range checksumAssetNames {
+			if !strings.EqualFold(candidate.Name, name) {
+				continue
+			}
+			contents, err := utils.DownloadText(candidate.URL)
+			if err != nil {
+				output.Printf("⚠️  Unable to read %s: %s\n", candidate.Name, err.Error())
+				continue
+			}
+			if digest := findChecksum(contents, asset.Name); digest != "" {
+				return digest
+			}
+		}
+	}
+
+	return ""
+}
+
+// findChecksum looks up filename in sha256sum-style contents ("<hex>  <name>").
+// A file containing a single bare digest is also accepted.
+func findChecksum(contents, filename string) string {
+	lines := strings.Split(strings.TrimSpace(contents), "\n")
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 1 && len(lines) == 1 {
+			return utils.NormalizeSHA256(fields[0])
+		}
+		if len(fields) >= 2 && strings.TrimPrefix(fields[len(fields)-1], "*") == filename {
+			return utils.NormalizeSHA256(fields[0])
+		}
+	}
+	return ""
+}
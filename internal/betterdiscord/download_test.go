@@ -0,0 +1,226 @@
+package betterdiscord
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newAsarServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("new asar")) //nolint:errcheck
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func sha256Hex(t *testing.T, contents string) string {
+	t.Helper()
+	sum := sha256.Sum256([]byte(contents))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestDownloadVerified_Mismatch(t *testing.T) {
+	server := newAsarServer(t)
+
+	install := New(filepath.Join(t.TempDir(), "BetterDiscord"))
+	os.MkdirAll(install.Data(), 0755)                      //nolint:errcheck
+	os.WriteFile(install.Asar(), []byte("old asar"), 0644) //nolint:errcheck
+
+	if _, err := install.downloadVerified(server.URL, strings.Repeat("0", 64)); err == nil {
+		t.Fatal("downloadVerified() should fail on checksum mismatch")
+	}
+
+	contents, _ := os.ReadFile(install.Asar())
+	if string(contents) != "old asar" {
+		t.Errorf("asar was modified on mismatch: %q", string(contents))
+	}
+	entries, _ := os.ReadDir(install.Data())
+	if len(entries) != 1 {
+		t.Errorf("temporary download should be removed on mismatch, found %d entries", len(entries))
+	}
+}
+
+func TestDownloadVerified_Match(t *testing.T) {
+	server := newAsarServer(t)
+
+	install := New(filepath.Join(t.TempDir(), "BetterDiscord"))
+	os.MkdirAll(install.Data(), 0755)                      //nolint:errcheck
+	os.WriteFile(install.Asar(), []byte("old asar"), 0644) //nolint:errcheck
+
+	digest := sha256Hex(t, "new asar")
+	if err := install.SetExpectedSHA256("SHA256:" + digest); err != nil {
+		t.Fatalf("SetExpectedSHA256() failed: %v", err)
+	}
+
+	if _, err := install.downloadVerified(server.URL, digest); err != nil {
+		t.Fatalf("downloadVerified() failed: %v", err)
+	}
+
+	contents, _ := os.ReadFile(install.Asar())
+	if string(contents) != "new asar" {
+		t.Errorf("asar was not replaced: %q", string(contents))
+	}
+}
+
+func TestDownloadVerified_ExpectedMismatch(t *testing.T) {
+	server := newAsarServer(t)
+
+	install := New(filepath.Join(t.TempDir(), "BetterDiscord"))
+	os.MkdirAll(install.Data(), 0755) //nolint:errcheck
+
+	if err := install.SetExpectedSHA256(strings.Repeat("a", 64)); err != nil {
+		t.Fatalf("SetExpectedSHA256() failed: %v", err)
+	}
+
+	if _, err := install.downloadVerified(server.URL, ""); err == nil {
+		t.Fatal("downloadVerified() should fail when the expected digest does not match")
+	}
+	if _, err := os.Stat(install.Asar()); !os.IsNotExist(err) {
+		t.Error("asar should not be created on mismatch")
+	}
+}
+
+func TestSetExpectedSHA256_Invalid(t *testing.T) {
+	install := New("/test/path")
+	if err := install.SetExpectedSHA256("not-a-digest"); err == nil {
+		t.Error("SetExpectedSHA256() should reject invalid digests")
+	}
+	if err := install.SetExpectedSHA256(""); err != nil {
+		t.Errorf("SetExpectedSHA256() should accept an empty digest: %v", err)
+	}
+}
+
+func TestFindChecksum(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		expected string
+	}{
+		{
+			name:     "sha256sum format",
+			contents: "aaaa  other.zip\nBBBB  betterdiscord.asar\n",
+			expected: "bbbb",
+		},
+		{
+			name:     "binary marker",
+			contents: "cccc *betterdiscord.asar\n",
+			expected: "cccc",
+		},
+		{
+			name:     "bare digest",
+			contents: "dddd\n",
+			expected: "dddd",
+		},
+		{
+			name:     "not listed",
+			contents: "aaaa  other.zip\n",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := findChecksum(tt.contents, "betterdiscord.asar")
+			if result != tt.expected {
+				t.Errorf("findChecksum() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDownload_UnverifiedSiteFallsBack(t *testing.T) {
+	tests := []struct {
+		name    string
+		release string
+	}{
+		{"no checksum published", `{"tag_name": "v1.2.3", "assets": [{"name": "betterdiscord.asar"}]}`},
+		{"checksum mismatch", `{"tag_name": "v1.2.3", "assets": [{"name": "betterdiscord.asar", "digest": "sha256:` + strings.Repeat("0", 64) + `"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/Download/betterdiscord.asar", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("x-bd-version", "1.2.3")
+				w.Write([]byte("unverified asar")) //nolint:errcheck
+			})
+			mux.HandleFunc("/releases/tags/v1.2.3", func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.release)) //nolint:errcheck
+			})
+			mux.HandleFunc("/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"tag_name": "v1.2.3", "assets": [{"name": "betterdiscord.asar", "url": "http://` + r.Host + `/asset", "digest": "sha256:` + sha256Hex(t, "github asar") + `"}]}`)) //nolint:errcheck
+			})
+			mux.HandleFunc("/asset", func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("github asar")) //nolint:errcheck
+			})
+			server := httptest.NewServer(mux)
+			t.Cleanup(server.Close)
+			useTestEndpoints(t, server)
+
+			install := New(filepath.Join(t.TempDir(), "BetterDiscord"))
+			os.MkdirAll(install.Data(), 0755) //nolint:errcheck
+
+			if err := install.download(); err != nil {
+				t.Fatalf("download() failed: %v", err)
+			}
+			contents, _ := os.ReadFile(install.Asar())
+			if string(contents) != "github asar" {
+				t.Errorf("asar = %q, expected the verified GitHub download", string(contents))
+			}
+		})
+	}
+}
+
+func TestDownload_NoDigestAnywhere(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/Download/betterdiscord.asar", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-bd-version", "1.2.3")
+		w.Write([]byte("unverified asar")) //nolint:errcheck
+	})
+	release := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tag_name": "v1.2.3", "assets": [{"name": "betterdiscord.asar", "url": "http://` + r.Host + `/asset"}]}`)) //nolint:errcheck
+	}
+	mux.HandleFunc("/releases/tags/v1.2.3", release)
+	mux.HandleFunc("/releases/latest", release)
+	mux.HandleFunc("/asset", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("github asar")) //nolint:errcheck
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	useTestEndpoints(t, server)
+
+	install := New(filepath.Join(t.TempDir(), "BetterDiscord"))
+	os.MkdirAll(install.Data(), 0755)                      //nolint:errcheck
+	os.WriteFile(install.Asar(), []byte("old asar"), 0644) //nolint:errcheck
+
+	err := install.download()
+	if err == nil {
+		t.Fatal("download() should fail when no checksum is published and none is expected")
+	}
+	if !strings.Contains(err.Error(), "--expect-sha256") {
+		t.Errorf("error should suggest --expect-sha256, got: %v", err)
+	}
+	contents, _ := os.ReadFile(install.Asar())
+	if string(contents) != "old asar" {
+		t.Errorf("asar was replaced without verification: %q", string(contents))
+	}
+
+	if err := install.SetExpectedSHA256(sha256Hex(t, "unverified asar")); err != nil {
+		t.Fatalf("SetExpectedSHA256() failed: %v", err)
+	}
+	if err := install.download(); err != nil {
+		t.Fatalf("download() with --expect-sha256 failed: %v", err)
+	}
+	contents, _ = os.ReadFile(install.Asar())
+	if string(contents) != "unverified asar" {
+		t.Errorf("asar = %q, expected the download matching --expect-sha256", string(contents))
+	}
+}
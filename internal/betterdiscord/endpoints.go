@@ -0,0 +1,98 @@
+package betterdiscord
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/betterdiscord/cli/internal/models"
+	"github.com/betterdiscord/cli/internal/utils"
+)
+
+// Endpoints are the base URLs the CLI talks to. Each one can be pointed at a
+// mirror that serves the same paths as the official service.
+type Endpoints struct {
+	// Store is the store API, serving /<kind> catalogues and /<id|name> entries.
+	Store string `json:"store"`
+	// Site is the website, serving /Download/betterdiscord.asar and /gh-redirect.
+	Site string `json:"site"`
+	// Releases is the GitHub releases API of the BetterDiscord repository.
+	Releases string `json:"releases"`
+}
+
+// DefaultEndpoints returns the official BetterDiscord and GitHub endpoints.
+func DefaultEndpoints() Endpoints {
+	return Endpoints{
+		Store:    "https://api.betterdiscord.app/v3/store",
+		Site:     "https://betterdiscord.app",
+		Releases: "https://api.github.com/repos/BetterDiscord/BetterDiscord/releases",
+	}
+}
+
+var endpoints = DefaultEndpoints()
+
+// GetEndpoints returns the endpoints currently in use.
+func GetEndpoints() Endpoints {
+	return endpoints
+}
+
+// SetEndpoints replaces the endpoints in use. Empty fields keep their default.
+func SetEndpoints(e Endpoints) error {
+	defaults := DefaultEndpoints()
+	resolved := Endpoints{}
+
+	for _, field := range []struct {
+		name     string
+		value    string
+		fallback string
+		target   *string
+	}{
+		{"store", e.Store, defaults.Store, &resolved.Store},
+		{"site", e.Site, defaults.Site, &resolved.Site},
+		{"releases", e.Releases, defaults.Releases, &resolved.Releases},
+	} {
+		value := strings.TrimRight(strings.TrimSpace(field.value), "/")
+		if value == "" {
+			*field.target = field.fallback
+			continue
+		}
+		parsed, err := url.Parse(value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("invalid %s endpoint %q: expected an http(s) URL", field.name, field.value)
+		}
+		*field.target = value
+	}
+
+	endpoints = resolved
+	return nil
+}
+
+// FetchLatestRelease returns the latest BetterDiscord release from the releases endpoint.
+func FetchLatestRelease() (*models.GitHubRelease, error) {
+	release, err := utils.DownloadJSON[models.GitHubRelease](endpoints.Releases + "/latest")
+	if err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// FetchRelease returns the BetterDiscord release tagged tag from the releases endpoint.
+func FetchRelease(tag string) (*models.GitHubRelease, error) {
+	release, err := utils.DownloadJSON[models.GitHubRelease](endpoints.Releases + "/tags/" + url.PathEscape(tag))
+	if err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+func storeURL(path string) string {
+	return endpoints.Store + "/" + path
+}
+
+func asarDownloadURL() string {
+	return endpoints.Site + "/Download/betterdiscord.asar"
+}
+
+func addonRedirectURL(id int) string {
+	return fmt.Sprintf("%s/gh-redirect?id=%d", endpoints.Site, id)
+}
@@ -0,0 +1,125 @@
+package betterdiscord
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/betterdiscord/cli/internal/utils"
+)
+
+// useTestEndpoints points every endpoint at server and disables the store
+// cache for the duration of the test.
+func useTestEndpoints(t *testing.T, server *httptest.Server) {
+	t.Helper()
+	previous, previousCache := endpoints, StoreCache
+	t.Cleanup(func() {
+		endpoints, StoreCache = previous, previousCache
+	})
+
+	StoreCache = &utils.Cache{}
+	err := SetEndpoints(Endpoints{
+		Store:    server.URL + "/v3/store",
+		Site:     server.URL,
+		Releases: server.URL + "/releases",
+	})
+	if err != nil {
+		t.Fatalf("SetEndpoints() failed: %v", err)
+	}
+}
+
+func newStoreServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v3/store/plugins", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id":1,"name":"Example","type":"plugin","version":"2.0.0"}]`)) //nolint:errcheck
+	})
+	mux.HandleFunc("/v3/store/Example", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":1,"name":"Example","type":"plugin","version":"2.0.0"}`)) //nolint:errcheck
+	})
+	mux.HandleFunc("/gh-redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/files/Example.plugin.js?id="+r.URL.Query().Get("id"), http.StatusFound)
+	})
+	mux.HandleFunc("/files/Example.plugin.js", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(validPlugin)) //nolint:errcheck
+	})
+	mux.HandleFunc("/releases/tags/v1.2.3", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tag_name": "v1.2.3", "assets": [{"name": "betterdiscord.asar", "digest": "sha256:` + sha256Hex(t, "mirrored asar") + `"}]}`)) //nolint:errcheck
+	})
+	mux.HandleFunc("/Download/betterdiscord.asar", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-bd-version", "1.2.3")
+		w.Write([]byte("mirrored asar")) //nolint:errcheck
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSetEndpoints(t *testing.T) {
+	previous := endpoints
+	t.Cleanup(func() { endpoints = previous })
+
+	if err := SetEndpoints(Endpoints{Store: "https://mirror.example/store/"}); err != nil {
+		t.Fatalf("SetEndpoints() failed: %v", err)
+	}
+	got := GetEndpoints()
+	if got.Store != "https://mirror.example/store" {
+		t.Errorf("Store = %s, expected trailing slash to be trimmed", got.Store)
+	}
+	if got.Site != DefaultEndpoints().Site || got.Releases != DefaultEndpoints().Releases {
+		t.Errorf("empty endpoints should keep their defaults, got %+v", got)
+	}
+
+	for _, invalid := range []string{"mirror.example", "ftp://mirror.example", "https://"} {
+		if err := SetEndpoints(Endpoints{Site: invalid}); err == nil {
+			t.Errorf("SetEndpoints() should reject %q", invalid)
+		}
+	}
+}
+
+func TestStore_CustomEndpoints(t *testing.T) {
+	server := newStoreServer(t)
+	useTestEndpoints(t, server)
+
+	addon, err := FetchAddonFromStore("Example")
+	if err != nil {
+		t.Fatalf("FetchAddonFromStore() failed: %v", err)
+	}
+	if addon.ID != 1 || addon.Version != "2.0.0" {
+		t.Errorf("unexpected addon %+v", addon)
+	}
+
+	addons, err := FetchAddonsOfType("plugin")
+	if err != nil {
+		t.Fatalf("FetchAddonsOfType() failed: %v", err)
+	}
+	if len(addons) != 1 || addons[0].Name != "Example" {
+		t.Errorf("unexpected catalogue %+v", addons)
+	}
+
+	downloadURL, err := GetAddonDownloadURL(1)
+	if err != nil {
+		t.Fatalf("GetAddonDownloadURL() failed: %v", err)
+	}
+	if downloadURL != server.URL+"/files/Example.plugin.js?id=1" {
+		t.Errorf("GetAddonDownloadURL() = %s", downloadURL)
+	}
+}
+
+func TestDownload_CustomEndpoints(t *testing.T) {
+	server := newStoreServer(t)
+	useTestEndpoints(t, server)
+
+	install := New(filepath.Join(t.TempDir(), "BetterDiscord"))
+	os.MkdirAll(install.Data(), 0755) //nolint:errcheck
+
+	if err := install.download(); err != nil {
+		t.Fatalf("download() failed: %v", err)
+	}
+	contents, _ := os.ReadFile(install.Asar())
+	if string(contents) != "mirrored asar" {
+		t.Errorf("asar = %q, expected the mirrored download", string(contents))
+	}
+}
@@ -0,0 +1,222 @@
+package betterdiscord
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/betterdiscord/cli/internal/dryrun"
+	"github.com/betterdiscord/cli/internal/utils"
+)
+
+// HistoryLimit is the number of previous versions kept per addon.
+var HistoryLimit = 5
+
+// HistoryEntry describes a previous version of an addon kept for rollback.
+type HistoryEntry struct {
+	Version string    `json:"version"`
+	Saved   time.Time `json:"saved"`
+	File    string    `json:"file"`
+	SHA256  string    `json:"sha256"`
+}
+
+// AddonHistory returns the stored previous versions of an addon, newest first.
+func AddonHistory(kind AddonKind, identifier string) ([]HistoryEntry, error) {
+	dir, _, err := addonHistory(kind, identifier)
+	if err != nil {
+		return nil, err
+	}
+	return readHistory(dir)
+}
+
+// RollbackAddon restores a previous version of an addon from its history.
+// If version is empty the most recent entry is used. The replaced file is kept as
+// .bak and recorded in the history, so the rollback can itself be rolled back.
+// An addon that was removed, for example by sync, is installed again.
+func RollbackAddon(kind AddonKind, identifier, version string) (*HistoryEntry, error) {
+	dir, dest, err := addonHistory(kind, identifier)
+	if err != nil {
+		return nil, err
+	}
+	return rollback(dir, dest, version)
+}
+
+// addonHistory returns the history folder of identifier and the path its
+// file is installed at. An addon that is no longer installed is looked up by
+// filename, so its history can still be restored.
+func addonHistory(kind AddonKind, identifier string) (dir, dest string, err error) {
+	if existing := FindAddon(kind, identifier); existing != nil {
+		dir, err := historyDir(kind, existing.FullFilename)
+		return dir, existing.Path, err
+	}
+
+	folder, err := addonDir(kind)
+	if err != nil {
+		return "", "", err
+	}
+	for _, name := range candidateFilenames(kind, identifier) {
+		if filepath.Base(name) != name {
+			continue
+		}
+		dir, err := historyDir(kind, name)
+		if err != nil {
+			return "", "", err
+		}
+		if utils.Exists(filepath.Join(dir, "history.json")) {
+			return dir, filepath.Join(folder, name), nil
+		}
+	}
+	return "", "", fmt.Errorf("addon %s not found", identifier)
+}
+
+// rollback restores the history entry matching version (or the newest one)
+// from dir over dest. The restored entry leaves the history because it is the
+// installed version again, and the version it replaces is recorded in its place.
+func rollback(dir, dest, version string) (*HistoryEntry, error) {
+	entries, err := readHistory(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no history available for %s", filepath.Base(dest))
+	}
+
+	index := 0
+	if version != "" {
+		index = -1
+		for i, entry := range entries {
+			if strings.TrimPrefix(entry.Version, "v") == strings.TrimPrefix(version, "v") {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			return nil, fmt.Errorf("version %s not found in history for %s", version, filepath.Base(dest))
+		}
+	}
+
+	selected := entries[index]
+	if dryrun.Skip(dryrun.WriteFile, dest, "restore v"+selected.Version+" from history") {
+		return &selected, nil
+	}
+
+	hadPrevious := utils.Exists(dest)
+	if err := restoreSnapshot(filepath.Join(dir, selected.File), dest); err != nil {
+		return nil, err
+	}
+
+	remaining := append(entries[:index:index], entries[index+1:]...)
+	if err := writeHistory(dir, remaining); err != nil {
+		return nil, err
+	}
+	os.Remove(filepath.Join(dir, selected.File)) //nolint:errcheck
+
+	if hadPrevious {
+		if err := recordHistory(dir, dest+".bak"); err != nil {
+			return nil, fmt.Errorf("restored v%s but could not save the replaced version to history: %w", selected.Version, err)
+		}
+	}
+
+	return &selected, nil
+}
+
+// recordHistory stores snapshot as the newest entry in the history folder dir,
+// pruning the history down to HistoryLimit entries.
+func recordHistory(dir, snapshot string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	entries, err := readHistory(dir)
+	if err != nil {
+		return err
+	}
+
+	contents, err := os.ReadFile(snapshot)
+	if err != nil {
+		return err
+	}
+	hash, err := utils.SHA256File(snapshot)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	entry := HistoryEntry{
+		Version: parseJSDoc(string(contents)).Version,
+		Saved:   now,
+		File:    fmt.Sprintf("%d.snapshot", now.UnixNano()),
+		SHA256:  hash,
+	}
+	if err := utils.CopyFile(snapshot, filepath.Join(dir, entry.File)); err != nil {
+		return err
+	}
+
+	entries = append([]HistoryEntry{entry}, entries...)
+	if len(entries) > HistoryLimit {
+		for _, old := range entries[HistoryLimit:] {
+			os.Remove(filepath.Join(dir, old.File)) //nolint:errcheck
+		}
+		entries = entries[:HistoryLimit]
+	}
+
+	return writeHistory(dir, entries)
+}
+
+// restoreSnapshot validates snapshot and swaps it in for dest, keeping the
+// replaced file as dest.bak.
+func restoreSnapshot(snapshot, dest string) error {
+	tmp := dest + ".restore"
+	if err := utils.CopyFile(snapshot, tmp); err != nil {
+		return err
+	}
+	if err := validateAddonFile(tmp); err != nil {
+		os.Remove(tmp) //nolint:errcheck
+		return err
+	}
+	if utils.Exists(dest) {
+		if err := utils.CopyFile(dest, dest+".bak"); err != nil {
+			os.Remove(tmp) //nolint:errcheck
+			return err
+		}
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		os.Remove(tmp) //nolint:errcheck
+		return err
+	}
+	return nil
+}
+
+func historyDir(kind AddonKind, filename string) (string, error) {
+	if _, err := addonDir(kind); err != nil {
+		return "", err
+	}
+	return filepath.Join(GetInstallation().Data(), "history", string(kind)+"s", strings.ToLower(filename)), nil
+}
+
+func readHistory(dir string) ([]HistoryEntry, error) {
+	contents, err := os.ReadFile(filepath.Join(dir, "history.json"))
+	if os.IsNotExist(err) {
+		return []HistoryEntry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []HistoryEntry
+	if err := json.Unmarshal(contents, &entries); err != nil {
+		return nil, fmt.Errorf("invalid history index in %s: %w", dir, err)
+	}
+	return entries, nil
+}
+
+func writeHistory(dir string, entries []HistoryEntry) error {
+	contents, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "history.json"), contents, 0644)
+}
@@ -0,0 +1,166 @@
+package betterdiscord
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func pluginVersion(version string) string {
+	return fmt.Sprintf("/**\n * @name Example\n * @version %s\n */\n", version)
+}
+
+func TestRecordHistory(t *testing.T) {
+	tmpDir := t.TempDir()
+	historyPath := filepath.Join(tmpDir, "history")
+	snapshot := filepath.Join(tmpDir, "Example.plugin.js.bak")
+
+	for _, version := range []string{"1.0.0", "1.1.0"} {
+		os.WriteFile(snapshot, []byte(pluginVersion(version)), 0644) //nolint:errcheck
+		if err := recordHistory(historyPath, snapshot); err != nil {
+			t.Fatalf("recordHistory() failed: %v", err)
+		}
+	}
+
+	entries, err := readHistory(historyPath)
+	if err != nil {
+		t.Fatalf("readHistory() failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Version != "1.1.0" || entries[1].Version != "1.0.0" {
+		t.Errorf("Entries should be newest first, got %s then %s", entries[0].Version, entries[1].Version)
+	}
+	for _, entry := range entries {
+		if _, err := os.Stat(filepath.Join(historyPath, entry.File)); err != nil {
+			t.Errorf("Snapshot file missing for %s: %v", entry.Version, err)
+		}
+	}
+}
+
+func TestRecordHistory_Limit(t *testing.T) {
+	tmpDir := t.TempDir()
+	historyPath := filepath.Join(tmpDir, "history")
+	snapshot := filepath.Join(tmpDir, "Example.plugin.js.bak")
+
+	original := HistoryLimit
+	HistoryLimit = 2
+	defer func() { HistoryLimit = original }()
+
+	for _, version := range []string{"1.0.0", "1.1.0", "1.2.0"} {
+		os.WriteFile(snapshot, []byte(pluginVersion(version)), 0644) //nolint:errcheck
+		if err := recordHistory(historyPath, snapshot); err != nil {
+			t.Fatalf("recordHistory() failed: %v", err)
+		}
+	}
+
+	entries, _ := readHistory(historyPath)
+	if len(entries) != 2 {
+		t.Fatalf("Expected history to be pruned to 2 entries, got %d", len(entries))
+	}
+	if entries[1].Version != "1.1.0" {
+		t.Errorf("Oldest entry should be pruned, got %s", entries[1].Version)
+	}
+
+	files, _ := os.ReadDir(historyPath)
+	if len(files) != 3 { // 2 snapshots + index
+		t.Errorf("Pruned snapshot files should be deleted, found %d files", len(files))
+	}
+}
+
+func TestRollback(t *testing.T) {
+	tmpDir := t.TempDir()
+	historyPath := filepath.Join(tmpDir, "history")
+	dest := filepath.Join(tmpDir, "Example.plugin.js")
+	snapshot := dest + ".bak"
+
+	for _, version := range []string{"1.0.0", "1.1.0"} {
+		os.WriteFile(snapshot, []byte(pluginVersion(version)), 0644) //nolint:errcheck
+		recordHistory(historyPath, snapshot)                         //nolint:errcheck
+	}
+	os.WriteFile(dest, []byte(pluginVersion("1.2.0")), 0644) //nolint:errcheck
+
+	entry, err := rollback(historyPath, dest, "1.0.0")
+	if err != nil {
+		t.Fatalf("rollback() failed: %v", err)
+	}
+	if entry.Version != "1.0.0" {
+		t.Errorf("rollback() restored %s, expected 1.0.0", entry.Version)
+	}
+
+	contents, _ := os.ReadFile(dest)
+	if string(contents) != pluginVersion("1.0.0") {
+		t.Errorf("dest was not restored, got %q", string(contents))
+	}
+
+	backup, _ := os.ReadFile(dest + ".bak")
+	if string(backup) != pluginVersion("1.2.0") {
+		t.Errorf("replaced version should be kept as .bak, got %q", string(backup))
+	}
+
+	entries, _ := readHistory(historyPath)
+	if len(entries) != 2 || entries[0].Version != "1.2.0" || entries[1].Version != "1.1.0" {
+		t.Errorf("history should hold the replaced 1.2.0 and the untouched 1.1.0, got %+v", entries)
+	}
+}
+
+func TestRollback_RoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	historyPath := filepath.Join(tmpDir, "history")
+	dest := filepath.Join(tmpDir, "Example.plugin.js")
+	snapshot := dest + ".bak"
+
+	for _, version := range []string{"1.0.0", "1.1.0"} {
+		os.WriteFile(snapshot, []byte(pluginVersion(version)), 0644) //nolint:errcheck
+		recordHistory(historyPath, snapshot)                         //nolint:errcheck
+	}
+	os.WriteFile(dest, []byte(pluginVersion("1.2.0")), 0644) //nolint:errcheck
+
+	for _, version := range []string{"1.1.0", "1.0.0", "1.2.0"} {
+		entry, err := rollback(historyPath, dest, version)
+		if err != nil {
+			t.Fatalf("rollback(%s) failed: %v", version, err)
+		}
+		if entry.Version != version {
+			t.Errorf("rollback(%s) restored %s", version, entry.Version)
+		}
+		contents, _ := os.ReadFile(dest)
+		if string(contents) != pluginVersion(version) {
+			t.Errorf("rollback(%s) left %q installed", version, string(contents))
+		}
+	}
+
+	entries, _ := readHistory(historyPath)
+	if len(entries) != 2 {
+		t.Fatalf("history should still hold the other two versions, got %+v", entries)
+	}
+	for _, entry := range entries {
+		if entry.Version == "1.2.0" {
+			t.Error("the installed version should not also be in history")
+		}
+		if _, err := os.Stat(filepath.Join(historyPath, entry.File)); err != nil {
+			t.Errorf("snapshot for %s is missing: %v", entry.Version, err)
+		}
+	}
+}
+
+func TestRollback_NoHistory(t *testing.T) {
+	tmpDir := t.TempDir()
+	if _, err := rollback(filepath.Join(tmpDir, "history"), filepath.Join(tmpDir, "Example.plugin.js"), ""); err == nil {
+		t.Error("rollback() should fail when there is no history")
+	}
+}
+
+func TestRollback_UnknownVersion(t *testing.T) {
+	tmpDir := t.TempDir()
+	historyPath := filepath.Join(tmpDir, "history")
+	snapshot := filepath.Join(tmpDir, "Example.plugin.js.bak")
+	os.WriteFile(snapshot, []byte(pluginVersion("1.0.0")), 0644) //nolint:errcheck
+	recordHistory(historyPath, snapshot)                         //nolint:errcheck
+
+	if _, err := rollback(historyPath, filepath.Join(tmpDir, "Example.plugin.js"), "9.9.9"); err == nil {
+		t.Error("rollback() should fail for a version not in history")
+	}
+}
@@ -1,10 +1,13 @@
 package betterdiscord
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sync"
 
+	"github.com/betterdiscord/cli/internal/dryrun"
 	"github.com/betterdiscord/cli/internal/models"
 	"github.com/betterdiscord/cli/internal/output"
 	"github.com/betterdiscord/cli/internal/utils"
@@ -12,13 +15,14 @@ import (
 )
 
 type BDInstall struct {
-	root          string
-	data          string
-	asar          string
-	plugins       string
-	themes        string
-	hasDownloaded bool
-	Buildinfo     Buildinfo
+	root           string
+	data           string
+	asar           string
+	plugins        string
+	themes         string
+	hasDownloaded  bool
+	expectedSHA256 string
+	Buildinfo      Buildinfo
 }
 
 // Root returns the root directory path of the BetterDiscord installation
@@ -51,6 +55,17 @@ func (i *BDInstall) HasDownloaded() bool {
 	return i.hasDownloaded
 }
 
+// SetExpectedSHA256 requires the next download to match the given SHA-256 digest.
+// An empty digest clears the requirement.
+func (i *BDInstall) SetExpectedSHA256(digest string) error {
+	digest = utils.NormalizeSHA256(digest)
+	if digest != "" && !sha256Regex.MatchString(digest) {
+		return fmt.Errorf("invalid SHA-256 digest %q", digest)
+	}
+	i.expectedSHA256 = digest
+	return nil
+}
+
 // Download downloads the BetterDiscord asar file
 func (i *BDInstall) Download() error {
 	return i.download()
@@ -77,6 +92,10 @@ func (i *BDInstall) RemoveAll() error {
 		return nil
 	}
 
+	if dryrun.Skip(dryrun.Remove, i.root, "the whole BetterDiscord folder") {
+		return nil
+	}
+
 	if err := os.RemoveAll(i.root); err != nil {
 		output.Printf("❌ Failed to remove BetterDiscord folder: %s\n", i.root)
 		output.Printf("   %s\n", err.Error())
@@ -87,8 +106,30 @@ func (i *BDInstall) RemoveAll() error {
 	return nil
 }
 
+var sha256Regex = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
 var lock = &sync.Mutex{}
 var globalInstance *BDInstall
+var rootOverride string
+
+// SetRoot makes GetInstallation use root as the BetterDiscord folder instead
+// of deriving it from the user config directory. An empty root restores the default.
+func SetRoot(root string) {
+	lock.Lock()
+	defer lock.Unlock()
+	if root != "" {
+		root = filepath.Clean(root)
+	}
+	rootOverride = root
+	globalInstance = nil
+}
+
+// HasRootOverride reports whether a custom root was set with SetRoot.
+func HasRootOverride() bool {
+	lock.Lock()
+	defer lock.Unlock()
+	return rootOverride != ""
+}
 
 func GetInstallation(base ...string) *BDInstall {
 	if len(base) == 0 {
@@ -102,6 +143,11 @@ func GetInstallation(base ...string) *BDInstall {
 			return globalInstance
 		}
 
+		if rootOverride != "" {
+			globalInstance = New(rootOverride)
+			return globalInstance
+		}
+
 		// Default to user config directory
 		configDir, _ := os.UserConfigDir()
 
@@ -3,11 +3,25 @@ package betterdiscord
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
+	"github.com/betterdiscord/cli/internal/dryrun"
 	"github.com/betterdiscord/cli/internal/models"
 )
 
+// newTestInstall points the installation at a temporary folder with empty
+// plugins and themes folders.
+func newTestInstall(t *testing.T) *BDInstall {
+	t.Helper()
+	SetRoot(t.TempDir())
+	t.Cleanup(func() { SetRoot("") })
+	install := GetInstallation()
+	os.MkdirAll(install.Plugins(), 0755) //nolint:errcheck
+	os.MkdirAll(install.Themes(), 0755)  //nolint:errcheck
+	return install
+}
+
 func TestNew(t *testing.T) {
 	rootPath := "/test/root/BetterDiscord"
 	install := New(rootPath)
@@ -244,6 +258,25 @@ func TestGetInstallation_Singleton(t *testing.T) {
 	}
 }
 
+func TestGetInstallation_RootOverride(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "portable")
+	SetRoot(root)
+	t.Cleanup(func() { SetRoot("") })
+
+	install := GetInstallation()
+	if install.Root() != root {
+		t.Errorf("GetInstallation().Root() = %s, expected %s", install.Root(), root)
+	}
+	if install.Plugins() != filepath.Join(root, "plugins") {
+		t.Errorf("GetInstallation().Plugins() = %s, expected it under the override", install.Plugins())
+	}
+
+	SetRoot("")
+	if GetInstallation().Root() == root {
+		t.Error("SetRoot(\"\") should restore the default root")
+	}
+}
+
 func TestMakeDirectory(t *testing.T) {
 	tmpDir := t.TempDir()
 	testDir := filepath.Join(tmpDir, "test", "nested", "directory")
@@ -320,3 +353,42 @@ func TestBDInstall_PathStructure(t *testing.T) {
 		})
 	}
 }
+
+func TestBDInstall_DryRun(t *testing.T) {
+	dryrun.Enable(true)
+	t.Cleanup(func() { dryrun.Enable(false) })
+
+	rootPath := filepath.Join(t.TempDir(), "BetterDiscord")
+	install := New(rootPath)
+
+	if err := install.Prepare(); err != nil {
+		t.Fatalf("Prepare() failed: %v", err)
+	}
+	if err := install.Download(); err != nil {
+		t.Fatalf("Download() failed: %v", err)
+	}
+	if _, err := os.Stat(rootPath); !os.IsNotExist(err) {
+		t.Fatal("dry-run Prepare() and Download() should not touch the filesystem")
+	}
+
+	os.MkdirAll(filepath.Join(rootPath, "data", "stable"), 0755)                                //nolint:errcheck
+	os.WriteFile(filepath.Join(rootPath, "data", "stable", "plugins.json"), []byte("{}"), 0644) //nolint:errcheck
+	if err := install.Repair(models.Stable); err != nil {
+		t.Fatalf("Repair() failed: %v", err)
+	}
+	if err := install.RemoveAll(); err != nil {
+		t.Fatalf("RemoveAll() failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(rootPath, "data", "stable", "plugins.json")); err != nil {
+		t.Error("dry-run Repair() and RemoveAll() should not delete anything")
+	}
+
+	var kinds []dryrun.Kind
+	for _, action := range dryrun.Actions() {
+		kinds = append(kinds, action.Kind)
+	}
+	expected := []dryrun.Kind{dryrun.CreateDir, dryrun.CreateDir, dryrun.CreateDir, dryrun.Download, dryrun.Remove, dryrun.Remove}
+	if !slices.Equal(kinds, expected) {
+		t.Errorf("recorded actions = %v, expected %v", kinds, expected)
+	}
+}
@@ -0,0 +1,417 @@
+package betterdiscord
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/betterdiscord/cli/internal/dryrun"
+	"github.com/betterdiscord/cli/internal/output"
+	"github.com/betterdiscord/cli/internal/semver"
+	"github.com/betterdiscord/cli/internal/utils"
+)
+
+// LockfileVersion is the schema version written to new lockfiles.
+const LockfileVersion = 1
+
+// DefaultLockfileName is the lockfile name used when none is specified.
+const DefaultLockfileName = "bdcli.lock.json"
+
+// LockedAddonCache is the folder holding a copy of every locked addon file,
+// named by its SHA-256. Store sources always serve the latest release, so
+// sync restores the exact locked contents from here when it can.
+var LockedAddonCache = lockedAddonCacheDir()
+
+func lockedAddonCacheDir() string {
+	dir := utils.DefaultCacheDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "addons")
+}
+
+// Lockfile pins a set of plugins and themes to exact file contents.
+type Lockfile struct {
+	Version int           `json:"version"`
+	Plugins []LockedAddon `json:"plugins"`
+	Themes  []LockedAddon `json:"themes"`
+}
+
+// LockedAddon records everything needed to reproduce a single addon file.
+type LockedAddon struct {
+	ID       int    `json:"id,omitempty"`
+	Name     string `json:"name"`
+	Filename string `json:"filename"`
+	Version  string `json:"version"`
+	Source   string `json:"source,omitempty"`
+	SHA256   string `json:"sha256"`
+}
+
+// SyncResult summarizes the changes made by SyncLockfile.
+type SyncResult struct {
+	Installed []string `json:"installed"`
+	Updated   []string `json:"updated"`
+	Removed   []string `json:"removed"`
+	Unchanged []string `json:"unchanged"`
+	Failed    []string `json:"failed"`
+}
+
+// GenerateLockfile builds a lockfile from the currently installed addons,
+// using the store to resolve IDs and source URLs.
+func GenerateLockfile() (*Lockfile, error) {
+	plugins, err := lockAddons(AddonPlugin)
+	if err != nil {
+		return nil, err
+	}
+	themes, err := lockAddons(AddonTheme)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Lockfile{
+		Version: LockfileVersion,
+		Plugins: plugins,
+		Themes:  themes,
+	}, nil
+}
+
+// ReadLockfile loads and validates a lockfile from disk.
+func ReadLockfile(path string) (*Lockfile, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var lock Lockfile
+	if err := json.Unmarshal(contents, &lock); err != nil {
+		return nil, fmt.Errorf("invalid lockfile %s: %w", path, err)
+	}
+	if lock.Version != LockfileVersion {
+		return nil, fmt.Errorf("unsupported lockfile version %d (expected %d)", lock.Version, LockfileVersion)
+	}
+	return &lock, nil
+}
+
+// Write saves the lockfile to disk as indented JSON.
+func (l *Lockfile) Write(path string) error {
+	contents, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dryrun.Skip(dryrun.WriteFile, path, "lockfile") {
+		return nil
+	}
+	return os.WriteFile(path, append(contents, '\n'), 0644)
+}
+
+// SyncLockfile installs, updates, or removes addons until the plugins and
+// themes folders match the lockfile exactly.
+func SyncLockfile(lock *Lockfile) (*SyncResult, error) {
+	result := &SyncResult{
+		Installed: []string{},
+		Updated:   []string{},
+		Removed:   []string{},
+		Unchanged: []string{},
+		Failed:    []string{},
+	}
+	if err := syncAddons(AddonPlugin, lock.Plugins, result); err != nil {
+		return result, err
+	}
+	if err := syncAddons(AddonTheme, lock.Themes, result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// UpdateLockfile installs the release the store serves now for every addon
+// in lock that has a store ID, using UpdateInstalledAddon or InstallAddon,
+// and returns a lockfile of the installed addons along with the files that
+// changed. It pins a new lockfile when sync fails because a source no longer
+// serves the locked version.
+func UpdateLockfile(lock *Lockfile) (*Lockfile, []string, error) {
+	updated := []string{}
+	for _, kind := range []AddonKind{AddonPlugin, AddonTheme} {
+		entries := lock.Plugins
+		if kind == AddonTheme {
+			entries = lock.Themes
+		}
+
+		for _, entry := range entries {
+			if entry.ID == 0 {
+				continue
+			}
+			addon, err := FetchAddonFromStore(strconv.Itoa(entry.ID))
+			if err != nil {
+				return nil, updated, fmt.Errorf("failed to find %s in the store: %w", entry.Name, err)
+			}
+
+			installed := FindAddon(kind, entry.Filename)
+			if installed != nil && semver.Compare(addon.Version, installed.Meta.Version) == 0 {
+				continue
+			}
+			if installed != nil {
+				_, err = UpdateInstalledAddon(kind, installed, addon)
+			} else {
+				_, err = InstallAddon(kind, strconv.Itoa(entry.ID))
+			}
+			if err != nil {
+				return nil, updated, fmt.Errorf("failed to update %s: %w", entry.Name, err)
+			}
+			output.Printf("✅ Updated %s to %s\n", entry.Name, output.FormatVersion(addon.Version))
+			updated = append(updated, entry.Filename)
+		}
+	}
+
+	fresh, err := GenerateLockfile()
+	if err != nil {
+		return nil, updated, err
+	}
+	return fresh, updated, nil
+}
+
+func lockAddons(kind AddonKind) ([]LockedAddon, error) {
+	items, err := ListAddons(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	locked := []LockedAddon{}
+	for _, item := range items {
+		hash, err := utils.SHA256File(item.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		name := item.Meta.Name
+		if name == "" {
+			name = item.BaseName
+		}
+
+		entry := LockedAddon{
+			Name:     name,
+			Filename: item.FullFilename,
+			Version:  item.Meta.Version,
+			SHA256:   hash,
+		}
+
+		if addon, err := FetchAddonFromStore(name); err == nil {
+			entry.ID = addon.ID
+			// The source only serves the locked file while the store is on the same version
+			if semver.Compare(addon.Version, item.Meta.Version) == 0 {
+				entry.Source = addon.LatestSourceURL
+			} else {
+				output.Printf("⚠️  The store has %s %s but %s is installed; it can only be restored from the local copy\n", name, output.FormatVersion(addon.Version), output.FormatVersion(item.Meta.Version))
+			}
+		} else {
+			output.Printf("⚠️  %s was not found in the store; it can only be restored from the local copy\n", name)
+		}
+
+		if err := cacheLockedAddon(item.Path, hash); err != nil {
+			output.Printf("⚠️  Unable to keep a local copy of %s: %v\n", item.FullFilename, err)
+		}
+
+		locked = append(locked, entry)
+	}
+
+	sort.Slice(locked, func(i, j int) bool { return locked[i].Filename < locked[j].Filename })
+	return locked, nil
+}
+
+func syncAddons(kind AddonKind, locked []LockedAddon, result *SyncResult) error {
+	dir, err := addonDir(kind)
+	if err != nil {
+		return err
+	}
+
+	items, err := ListAddons(kind)
+	if err != nil {
+		return err
+	}
+
+	wanted := map[string]bool{}
+	for _, entry := range locked {
+		if filepath.Base(entry.Filename) != entry.Filename || !isAddonFile(kind, entry.Filename) {
+			return fmt.Errorf("invalid %s filename in lockfile: %q", kind, entry.Filename)
+		}
+		wanted[strings.ToLower(entry.Filename)] = true
+	}
+
+	// Remove anything that is not part of the lockfile
+	for _, item := range items {
+		if wanted[strings.ToLower(item.FullFilename)] {
+			continue
+		}
+		if !dryrun.Skip(dryrun.Remove, item.Path, "not in the lockfile") {
+			if err := removeSyncedAddon(kind, item.Path); err != nil {
+				output.Printf("❌ Failed to remove %s: %v\n", item.FullFilename, err)
+				result.Failed = append(result.Failed, item.FullFilename)
+				continue
+			}
+			output.Printf("🗑️  Removed %s\n", item.FullFilename)
+		}
+		result.Removed = append(result.Removed, item.FullFilename)
+	}
+
+	for _, entry := range locked {
+		dest := filepath.Join(dir, entry.Filename)
+		existed := false
+
+		if hash, err := utils.SHA256File(dest); err == nil {
+			if hash == entry.SHA256 {
+				result.Unchanged = append(result.Unchanged, entry.Filename)
+				continue
+			}
+			existed = true
+		}
+
+		if err := syncAddon(kind, dest, entry); err != nil {
+			output.Printf("❌ Failed to sync %s: %v\n", entry.Filename, err)
+			result.Failed = append(result.Failed, entry.Filename)
+			continue
+		}
+
+		if existed {
+			output.Printf("✅ Updated %s to v%s\n", entry.Filename, entry.Version)
+			result.Updated = append(result.Updated, entry.Filename)
+		} else {
+			output.Printf("✅ Installed %s v%s\n", entry.Filename, entry.Version)
+			result.Installed = append(result.Installed, entry.Filename)
+		}
+	}
+
+	return nil
+}
+
+// removeSyncedAddon removes an addon that is not in the lockfile, keeping it
+// as .bak and in its history so it can be rolled back.
+func removeSyncedAddon(kind AddonKind, path string) error {
+	hadPrevious, err := backupAddon(path)
+	if err != nil {
+		return err
+	}
+	if hadPrevious {
+		recordReplaced(kind, path)
+	}
+	return os.Remove(path)
+}
+
+// syncAddon installs the locked version of an addon at dest, from the local
+// copy kept when the lockfile was written or else from its source. Only
+// contents matching the recorded hash are written, and only to dest. A
+// replaced file is kept as .bak and in its history like any other update.
+//
+// This does not go through InstallAddon, which installs whatever the store
+// or URL serves now rather than the exact locked contents. Without a local
+// copy, a locked addon can only be reproduced while its source still serves
+// the locked version; UpdateLockfile is what moves a lockfile on.
+func syncAddon(kind AddonKind, dest string, entry LockedAddon) error {
+	if contents, ok := cachedLockedAddon(entry.SHA256); ok {
+		if dryrun.Skip(dryrun.WriteFile, dest, "locked copy of "+entry.Filename) {
+			return nil
+		}
+		return writeAddon(kind, dest, contents)
+	}
+
+	source, err := lockedSource(entry)
+	if err != nil {
+		return err
+	}
+	if dryrun.Skip(dryrun.Download, dest, "from "+source) {
+		return nil
+	}
+
+	hadPrevious := false
+	_, err = utils.DownloadFileVerified(source, dest, func(tmpPath string) error {
+		hash, err := utils.SHA256File(tmpPath)
+		if err != nil {
+			return err
+		}
+		if hash != entry.SHA256 {
+			return fmt.Errorf("hash mismatch for %s: expected %s, got %s; the source no longer serves the locked version and there is no local copy, run 'bdcli lock update' to pin the current release", entry.Filename, entry.SHA256, hash)
+		}
+		hadPrevious, err = backupAddon(dest)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if hadPrevious {
+		recordReplaced(kind, dest)
+	}
+
+	cacheLockedAddon(dest, entry.SHA256) //nolint:errcheck
+	return nil
+}
+
+// lockedSource returns the URL to download a locked addon from, falling back
+// to the store entry when the lockfile has no source.
+func lockedSource(entry LockedAddon) (string, error) {
+	if entry.Source != "" {
+		return entry.Source, nil
+	}
+	if entry.ID == 0 {
+		return "", fmt.Errorf("no source or local copy for %s", entry.Name)
+	}
+
+	addon, err := FetchAddonFromStore(strconv.Itoa(entry.ID))
+	if err != nil {
+		return "", err
+	}
+	if semver.Compare(addon.Version, entry.Version) != 0 {
+		return "", fmt.Errorf("the store now serves %s %s, not the locked %s, and there is no local copy; run 'bdcli lock update' to pin the current release", entry.Name, output.FormatVersion(addon.Version), output.FormatVersion(entry.Version))
+	}
+	return resolveDownloadURL(addon)
+}
+
+// lockedAddonPath returns where the local copy of the file with digest hash is
+// kept, or "" if hash is not a SHA-256 digest or there is no cache folder.
+func lockedAddonPath(hash string) string {
+	if decoded, err := hex.DecodeString(hash); err != nil || len(decoded) != sha256.Size || LockedAddonCache == "" {
+		return ""
+	}
+	return filepath.Join(LockedAddonCache, strings.ToLower(hash))
+}
+
+// cacheLockedAddon keeps a copy of the addon file at path under its digest.
+func cacheLockedAddon(path, hash string) error {
+	dest := lockedAddonPath(hash)
+	if dest == "" || utils.Exists(dest) {
+		return nil
+	}
+	if dryrun.Skip(dryrun.WriteFile, dest, "local copy of "+filepath.Base(path)) {
+		return nil
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(LockedAddonCache, 0755); err != nil {
+		return err
+	}
+	return utils.WriteFileAtomic(dest, contents, 0644)
+}
+
+// cachedLockedAddon returns the local copy of the file with digest hash, if
+// one is kept and still matches it.
+func cachedLockedAddon(hash string) ([]byte, bool) {
+	path := lockedAddonPath(hash)
+	if path == "" {
+		return nil, false
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	sum := sha256.Sum256(contents)
+	if hex.EncodeToString(sum[:]) != strings.ToLower(hash) {
+		return nil, false
+	}
+	return contents, true
+}
@@ -0,0 +1,245 @@
+package betterdiscord
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLockfile_WriteRead(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, DefaultLockfileName)
+
+	lock := &Lockfile{
+		Version: LockfileVersion,
+		Plugins: []LockedAddon{
+			{ID: 1, Name: "Foo", Filename: "Foo.plugin.js", Version: "1.0.0", Source: "https://example.com/Foo.plugin.js", SHA256: "abc"},
+		},
+		Themes: []LockedAddon{
+			{Name: "Bar", Filename: "Bar.theme.css", Version: "2.0.0", SHA256: "def"},
+		},
+	}
+
+	if err := lock.Write(path); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+
+	got, err := ReadLockfile(path)
+	if err != nil {
+		t.Fatalf("ReadLockfile() failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, lock) {
+		t.Errorf("ReadLockfile() = %#v\nwant %#v", got, lock)
+	}
+}
+
+func TestReadLockfile_UnsupportedVersion(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, DefaultLockfileName)
+	os.WriteFile(path, []byte(`{"version": 99, "plugins": [], "themes": []}`), 0644) //nolint:errcheck
+
+	if _, err := ReadLockfile(path); err == nil {
+		t.Error("ReadLockfile() should fail for an unsupported version")
+	}
+}
+
+func TestReadLockfile_InvalidJSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, DefaultLockfileName)
+	os.WriteFile(path, []byte(`not json`), 0644) //nolint:errcheck
+
+	if _, err := ReadLockfile(path); err == nil {
+		t.Error("ReadLockfile() should fail for invalid JSON")
+	}
+}
+
+// useLockedAddonCache points the local copies of locked addons at a temporary folder.
+func useLockedAddonCache(t *testing.T) string {
+	t.Helper()
+	previous := LockedAddonCache
+	LockedAddonCache = t.TempDir()
+	t.Cleanup(func() { LockedAddonCache = previous })
+	return LockedAddonCache
+}
+
+func TestSyncLockfile(t *testing.T) {
+	files := map[string]string{
+		"New.plugin.js":   validPluginNamed("New"),
+		"Stale.plugin.js": validPluginNamed("Stale"),
+		"Keep.plugin.js":  "/**\n * @name Keep\n * @version 9.9.9\n */\n",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contents, ok := files[path.Base(r.URL.Path)]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(contents)) //nolint:errcheck
+	}))
+	t.Cleanup(server.Close)
+	useTestEndpoints(t, server)
+	useLockedAddonCache(t)
+
+	install := newTestInstall(t)
+	keep := validPluginNamed("Keep")
+	os.WriteFile(filepath.Join(install.Plugins(), "Keep.plugin.js"), []byte(keep), 0644)                          //nolint:errcheck
+	os.WriteFile(filepath.Join(install.Plugins(), "Stale.plugin.js"), []byte(validPluginNamed("Outdated")), 0644) //nolint:errcheck
+	os.WriteFile(filepath.Join(install.Plugins(), "Extra.plugin.js"), []byte(validPluginNamed("Extra")), 0644)    //nolint:errcheck
+
+	lock := &Lockfile{
+		Version: LockfileVersion,
+		Plugins: []LockedAddon{
+			{Name: "Keep", Filename: "Keep.plugin.js", Source: server.URL + "/files/Keep.plugin.js", SHA256: sha256Hex(t, keep)},
+			{Name: "New", Filename: "New.plugin.js", Source: server.URL + "/files/New.plugin.js", SHA256: sha256Hex(t, files["New.plugin.js"])},
+			{Name: "Stale", Filename: "Stale.plugin.js", Source: server.URL + "/files/Stale.plugin.js", SHA256: sha256Hex(t, files["Stale.plugin.js"])},
+			// The source moved on, and its name matches another installed plugin
+			{Name: "Moved", Filename: "Moved.plugin.js", Source: server.URL + "/files/Keep.plugin.js", SHA256: sha256Hex(t, validPluginNamed("Moved"))},
+		},
+		Themes: []LockedAddon{},
+	}
+
+	result, err := SyncLockfile(lock)
+	if err != nil {
+		t.Fatalf("SyncLockfile() failed: %v", err)
+	}
+
+	expected := &SyncResult{
+		Installed: []string{"New.plugin.js"},
+		Updated:   []string{"Stale.plugin.js"},
+		Removed:   []string{"Extra.plugin.js"},
+		Unchanged: []string{"Keep.plugin.js"},
+		Failed:    []string{"Moved.plugin.js"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("SyncLockfile() = %+v\nwant %+v", result, expected)
+	}
+
+	for name, want := range map[string]string{
+		"Keep.plugin.js":  keep,
+		"New.plugin.js":   files["New.plugin.js"],
+		"Stale.plugin.js": files["Stale.plugin.js"],
+	} {
+		contents, _ := os.ReadFile(filepath.Join(install.Plugins(), name))
+		if string(contents) != want {
+			t.Errorf("%s = %q, expected %q", name, string(contents), want)
+		}
+	}
+	for _, name := range []string{"Extra.plugin.js", "Moved.plugin.js"} {
+		if _, err := os.Stat(filepath.Join(install.Plugins(), name)); err == nil {
+			t.Errorf("%s should not be installed", name)
+		}
+	}
+	items, _ := install.ListAddons(AddonPlugin)
+	if len(items) != 3 {
+		t.Errorf("sync should leave exactly the 3 locked plugins, found %d", len(items))
+	}
+
+	// Both the replaced and the removed plugin can be rolled back
+	if history, err := AddonHistory(AddonPlugin, "Stale"); err != nil || len(history) != 1 {
+		t.Errorf("AddonHistory(Stale) = %v, %v, expected the replaced version", history, err)
+	}
+	if _, err := RollbackAddon(AddonPlugin, "Extra.plugin.js", ""); err != nil {
+		t.Fatalf("RollbackAddon(Extra) failed: %v", err)
+	}
+	contents, _ := os.ReadFile(filepath.Join(install.Plugins(), "Extra.plugin.js"))
+	if string(contents) != validPluginNamed("Extra") {
+		t.Errorf("Extra.plugin.js = %q after rollback, expected the removed plugin", string(contents))
+	}
+}
+
+func TestGenerateLockfile_PinsContents(t *testing.T) {
+	installed := "/**\n * @name Example\n * @version 2.0.0\n */\n"
+	served := installed
+	storeVersion := "2.0.0"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/store/Example":
+			w.Write([]byte(`{"id": 1, "name": "Example", "version": "` + storeVersion + `", "latest_source_url": "http://` + r.Host + `/files/Example.plugin.js"}`)) //nolint:errcheck
+		case "/files/Example.plugin.js":
+			w.Write([]byte(served)) //nolint:errcheck
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	useTestEndpoints(t, server)
+	useLockedAddonCache(t)
+
+	install := newTestInstall(t)
+	dest := filepath.Join(install.Plugins(), "Example.plugin.js")
+	os.WriteFile(dest, []byte(installed), 0644) //nolint:errcheck
+
+	lock, err := GenerateLockfile()
+	if err != nil {
+		t.Fatalf("GenerateLockfile() failed: %v", err)
+	}
+	if len(lock.Plugins) != 1 || lock.Plugins[0].Source != server.URL+"/files/Example.plugin.js" {
+		t.Fatalf("GenerateLockfile() plugins = %+v, expected the store source", lock.Plugins)
+	}
+
+	// Upstream publishes a new release, the lockfile still reproduces the locked file
+	served = "/**\n * @name Example\n * @version 3.0.0\n */\n"
+	os.Remove(dest) //nolint:errcheck
+	if _, err := SyncLockfile(lock); err != nil {
+		t.Fatalf("SyncLockfile() failed: %v", err)
+	}
+	if contents, _ := os.ReadFile(dest); string(contents) != installed {
+		t.Errorf("sync installed %q, expected the locked contents", string(contents))
+	}
+
+	// A store on another version is not recorded as the source
+	storeVersion = "3.0.0"
+	lock, err = GenerateLockfile()
+	if err != nil {
+		t.Fatalf("GenerateLockfile() failed: %v", err)
+	}
+	if lock.Plugins[0].Source != "" || lock.Plugins[0].ID != 1 {
+		t.Errorf("GenerateLockfile() = %+v, expected an ID without a source", lock.Plugins[0])
+	}
+}
+
+func TestUpdateLockfile(t *testing.T) {
+	current := "/**\n * @name Example\n * @version 3.0.0\n */\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/store/1", "/v3/store/Example":
+			w.Write([]byte(`{"id": 1, "name": "Example", "version": "3.0.0", "latest_source_url": "http://` + r.Host + `/files/Example.plugin.js"}`)) //nolint:errcheck
+		case "/files/Example.plugin.js":
+			w.Write([]byte(current)) //nolint:errcheck
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	useTestEndpoints(t, server)
+	useLockedAddonCache(t)
+
+	install := newTestInstall(t)
+	dest := filepath.Join(install.Plugins(), "Example.plugin.js")
+	old := "/**\n * @name Example\n * @version 2.0.0\n */\n"
+	os.WriteFile(dest, []byte(old), 0644) //nolint:errcheck
+
+	lock := &Lockfile{
+		Version: LockfileVersion,
+		Plugins: []LockedAddon{{ID: 1, Name: "Example", Filename: "Example.plugin.js", Version: "2.0.0", SHA256: sha256Hex(t, old)}},
+		Themes:  []LockedAddon{},
+	}
+	fresh, updated, err := UpdateLockfile(lock)
+	if err != nil {
+		t.Fatalf("UpdateLockfile() failed: %v", err)
+	}
+	if len(updated) != 1 || updated[0] != "Example.plugin.js" {
+		t.Errorf("UpdateLockfile() updated = %v, expected Example.plugin.js", updated)
+	}
+	if len(fresh.Plugins) != 1 || fresh.Plugins[0].Version != "3.0.0" || fresh.Plugins[0].SHA256 != sha256Hex(t, current) {
+		t.Errorf("UpdateLockfile() plugins = %+v, expected Example pinned at 3.0.0", fresh.Plugins)
+	}
+	if contents, _ := os.ReadFile(dest); string(contents) != current {
+		t.Errorf("Example.plugin.js = %q, expected the current release", string(contents))
+	}
+}
@@ -9,17 +9,17 @@ var splitRegex = regexp.MustCompile(`(?m)[^\S\r\n]*?\r?(?:\r\n|\n)[^\S\r\n]*?\*[
 var escapedAtRegex = regexp.MustCompile(`^\\@`)
 
 type Meta struct {
-	Name        string
-	Author      string
-	Description string
-	Version     string
-	Invite      string
-	AuthorID    string
-	AuthorLink  string
-	Donate      string
-	Patreon     string
-	Website     string
-	Source      string
+	Name        string `json:"name,omitempty"`
+	Author      string `json:"author,omitempty"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version,omitempty"`
+	Invite      string `json:"invite,omitempty"`
+	AuthorID    string `json:"authorId,omitempty"`
+	AuthorLink  string `json:"authorLink,omitempty"`
+	Donate      string `json:"donate,omitempty"`
+	Patreon     string `json:"patreon,omitempty"`
+	Website     string `json:"website,omitempty"`
+	Source      string `json:"source,omitempty"`
 }
 
 func parseJSDoc(fileContent string) Meta {
@@ -0,0 +1,273 @@
+package betterdiscord
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/betterdiscord/cli/internal/dryrun"
+	"github.com/betterdiscord/cli/internal/utils"
+)
+
+const pluginConfigSuffix = ".config.json"
+
+// PluginConfig is the settings a plugin saves in <Name>.config.json next to
+// the plugin files.
+type PluginConfig map[string]any
+
+// PluginConfigName returns the name a plugin's settings are stored under.
+// Installed plugins are matched by name or filename; otherwise an existing
+// config file with the same name, regardless of case, is used.
+func PluginConfigName(identifier string) (string, error) {
+	if entry := FindAddon(AddonPlugin, identifier); entry != nil {
+		if entry.Meta.Name != "" {
+			return entry.Meta.Name, nil
+		}
+		return entry.BaseName, nil
+	}
+
+	names, err := pluginConfigNames()
+	if err != nil {
+		return "", err
+	}
+	for _, name := range names {
+		if strings.EqualFold(name, identifier) {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("plugin %s not found", identifier)
+}
+
+// PluginConfigPath returns the settings file for the plugin called name.
+func PluginConfigPath(name string) (string, error) {
+	dir, err := addonDir(AddonPlugin)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+pluginConfigSuffix), nil
+}
+
+// ReadPluginConfig reads the settings of the plugin called name. A missing
+// file means the plugin has not saved anything yet.
+func ReadPluginConfig(name string) (PluginConfig, error) {
+	path, err := PluginConfigPath(name)
+	if err != nil {
+		return nil, err
+	}
+	contents, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return PluginConfig{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	config := PluginConfig{}
+	if err := decodeConfigJSON(contents, &config); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// WritePluginConfig saves the settings of the plugin called name.
+func WritePluginConfig(name string, config PluginConfig) error {
+	path, err := PluginConfigPath(name)
+	if err != nil {
+		return err
+	}
+	contents, err := json.MarshalIndent(config, "", "    ")
+	if err != nil {
+		return err
+	}
+	if dryrun.Skip(dryrun.WriteFile, path, "settings for "+name) {
+		return nil
+	}
+	return utils.WriteFileAtomic(path, contents, 0644)
+}
+
+// RemovePluginConfig deletes the settings file of the plugin called name and
+// reports whether there was one.
+func RemovePluginConfig(name string) (bool, error) {
+	path, err := PluginConfigPath(name)
+	if err != nil {
+		return false, err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return false, nil
+	}
+	if dryrun.Skip(dryrun.Remove, path, "settings for "+name) {
+		return true, nil
+	}
+	return true, os.Remove(path)
+}
+
+// ExportPluginConfigs returns the settings of the named plugins keyed by
+// plugin name, or of every plugin with a settings file when names is empty.
+func ExportPluginConfigs(names []string) (map[string]PluginConfig, error) {
+	if len(names) == 0 {
+		all, err := pluginConfigNames()
+		if err != nil {
+			return nil, err
+		}
+		names = all
+	}
+
+	configs := make(map[string]PluginConfig, len(names))
+	for _, name := range names {
+		config, err := ReadPluginConfig(name)
+		if err != nil {
+			return nil, err
+		}
+		configs[name] = config
+	}
+	return configs, nil
+}
+
+// DecodePluginConfigs reads settings exported with ExportPluginConfigs, as
+// JSON or YAML.
+func DecodePluginConfigs(contents []byte) (map[string]PluginConfig, error) {
+	configs := map[string]PluginConfig{}
+	if err := yaml.Unmarshal(contents, &configs); err != nil {
+		return nil, fmt.Errorf("invalid plugin settings export: %w", err)
+	}
+	return configs, nil
+}
+
+// ImportPluginConfigs writes every plugin's settings from configs, replacing
+// what was saved before, and returns the plugin names in order.
+func ImportPluginConfigs(configs map[string]PluginConfig) ([]string, error) {
+	names := make([]string, 0, len(configs))
+	for name := range configs {
+		if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+			return nil, fmt.Errorf("invalid plugin name %q", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := WritePluginConfig(name, configs[name]); err != nil {
+			return nil, fmt.Errorf("failed to import settings for %s: %w", name, err)
+		}
+	}
+	return names, nil
+}
+
+// pluginConfigNames lists the plugins that have a settings file.
+func pluginConfigNames() ([]string, error) {
+	dir, err := addonDir(AddonPlugin)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), pluginConfigSuffix) {
+			names = append(names, strings.TrimSuffix(e.Name(), pluginConfigSuffix))
+		}
+	}
+	return names, nil
+}
+
+// Get returns the value at the dotted path key.
+func (c PluginConfig) Get(key string) (any, bool) {
+	var value any = map[string]any(c)
+	for _, part := range strings.Split(key, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[part]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// Set stores value at the dotted path key, creating objects along the way.
+func (c PluginConfig) Set(key string, value any) error {
+	parts := strings.Split(key, ".")
+	object := map[string]any(c)
+	for i, part := range parts[:len(parts)-1] {
+		next, exists := object[part]
+		if !exists {
+			child := map[string]any{}
+			object[part] = child
+			object = child
+			continue
+		}
+		child, ok := next.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s is not an object", strings.Join(parts[:i+1], "."))
+		}
+		object = child
+	}
+	object[parts[len(parts)-1]] = value
+	return nil
+}
+
+// Unset removes the value at the dotted path key and reports whether it was
+// there.
+func (c PluginConfig) Unset(key string) bool {
+	parts := strings.Split(key, ".")
+	parent := strings.Join(parts[:len(parts)-1], ".")
+
+	object := map[string]any(c)
+	if parent != "" {
+		value, ok := c.Get(parent)
+		if !ok {
+			return false
+		}
+		if object, ok = value.(map[string]any); !ok {
+			return false
+		}
+	}
+
+	last := parts[len(parts)-1]
+	if _, ok := object[last]; !ok {
+		return false
+	}
+	delete(object, last)
+	return true
+}
+
+// ParseConfigValue reads a value given on the command line as JSON, so
+// numbers, booleans, objects, and quoted strings keep their type. Anything
+// that is not valid JSON is taken as a plain string.
+func ParseConfigValue(raw string) any {
+	var value any
+	if err := decodeConfigJSON([]byte(raw), &value); err != nil {
+		return raw
+	}
+	return value
+}
+
+// decodeConfigJSON decodes contents into v, keeping numbers as json.Number.
+// Decoding them as float64 would round large integers such as Discord IDs
+// when the settings are written back.
+func decodeConfigJSON(contents []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return errors.New("unexpected data after the JSON value")
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package betterdiscord
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testPluginConfig() PluginConfig {
+	return PluginConfig{
+		"appearance": map[string]any{"color": "red", "size": float64(3)},
+		"enabled":    true,
+	}
+}
+
+func TestPluginConfig_Get(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected any
+		found    bool
+	}{
+		{"enabled", true, true},
+		{"appearance.color", "red", true},
+		{"appearance", map[string]any{"color": "red", "size": float64(3)}, true},
+		{"appearance.missing", nil, false},
+		{"enabled.nested", nil, false},
+		{"missing", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			value, found := testPluginConfig().Get(tt.key)
+			if found != tt.found || !reflect.DeepEqual(value, tt.expected) {
+				t.Errorf("Get(%q) = %v, %v, expected %v, %v", tt.key, value, found, tt.expected, tt.found)
+			}
+		})
+	}
+}
+
+func TestPluginConfig_Set(t *testing.T) {
+	config := testPluginConfig()
+
+	if err := config.Set("appearance.size", float64(5)); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+	if err := config.Set("new.deep.key", "hello"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+	if err := config.Set("enabled.nested", 1); err == nil {
+		t.Error("Set() should fail when the path goes through a value that is not an object")
+	}
+
+	if value, _ := config.Get("appearance.size"); value != float64(5) {
+		t.Errorf("appearance.size = %v, expected 5", value)
+	}
+	if value, _ := config.Get("new.deep.key"); value != "hello" {
+		t.Errorf("new.deep.key = %v, expected hello", value)
+	}
+	if value, _ := config.Get("appearance.color"); value != "red" {
+		t.Errorf("appearance.color = %v, expected it to be kept", value)
+	}
+}
+
+func TestPluginConfig_Unset(t *testing.T) {
+	tests := []struct {
+		key     string
+		removed bool
+	}{
+		{"enabled", true},
+		{"appearance.color", true},
+		{"appearance.missing", false},
+		{"missing.key", false},
+		{"enabled.nested", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			config := testPluginConfig()
+			if removed := config.Unset(tt.key); removed != tt.removed {
+				t.Errorf("Unset(%q) = %v, expected %v", tt.key, removed, tt.removed)
+			}
+			if _, found := config.Get(tt.key); found {
+				t.Errorf("%q is still set", tt.key)
+			}
+		})
+	}
+}
+
+func TestParseConfigValue(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected any
+	}{
+		{"5", json.Number("5")},
+		{"123456789012345678", json.Number("123456789012345678")},
+		{"true", true},
+		{`"5"`, "5"},
+		{"hello", "hello"},
+		{`{"a": 1}`, map[string]any{"a": json.Number("1")}},
+		{"null", nil},
+		{"5 6", "5 6"},
+	}
+
+	for _, tt := range tests {
+		if value := ParseConfigValue(tt.raw); !reflect.DeepEqual(value, tt.expected) {
+			t.Errorf("ParseConfigValue(%q) = %#v, expected %#v", tt.raw, value, tt.expected)
+		}
+	}
+}
+
+func TestPluginConfig_KeepsLargeIntegers(t *testing.T) {
+	plugins := newTestInstall(t).Plugins()
+
+	os.WriteFile(filepath.Join(plugins, "Example.config.json"), []byte(`{"id": 123456789012345678, "on": false}`), 0644) //nolint:errcheck
+
+	config, err := ReadPluginConfig("Example")
+	if err != nil {
+		t.Fatalf("ReadPluginConfig() failed: %v", err)
+	}
+	if err := config.Set("on", ParseConfigValue("true")); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+	if err := WritePluginConfig("Example", config); err != nil {
+		t.Fatalf("WritePluginConfig() failed: %v", err)
+	}
+
+	contents, _ := os.ReadFile(filepath.Join(plugins, "Example.config.json"))
+	if !strings.Contains(string(contents), "123456789012345678") {
+		t.Errorf("rewritten settings = %s, expected the id to keep every digit", contents)
+	}
+}
+
+func TestPluginConfigs_ExportImport(t *testing.T) {
+	plugins := newTestInstall(t).Plugins()
+
+	os.WriteFile(filepath.Join(plugins, "Crashy.plugin.js"), []byte(validPluginNamed("Crashy")), 0644) //nolint:errcheck
+	os.WriteFile(filepath.Join(plugins, "Crashy.config.json"), []byte(`{"size": 3}`), 0644)            //nolint:errcheck
+	os.WriteFile(filepath.Join(plugins, "Gone.config.json"), []byte(`{"on": true}`), 0644)             //nolint:errcheck
+
+	for identifier, expected := range map[string]string{"crashy.plugin.js": "Crashy", "gone": "Gone"} {
+		name, err := PluginConfigName(identifier)
+		if err != nil || name != expected {
+			t.Errorf("PluginConfigName(%q) = %q, %v, expected %q", identifier, name, err, expected)
+		}
+	}
+	if _, err := PluginConfigName("missing"); err == nil {
+		t.Error("PluginConfigName() should fail for an unknown plugin")
+	}
+
+	configs, err := ExportPluginConfigs(nil)
+	if err != nil {
+		t.Fatalf("ExportPluginConfigs() failed: %v", err)
+	}
+	if len(configs) != 2 || configs["Crashy"]["size"] != json.Number("3") {
+		t.Fatalf("ExportPluginConfigs() = %v", configs)
+	}
+
+	for _, name := range []string{"Crashy", "Gone"} {
+		if removed, err := RemovePluginConfig(name); err != nil || !removed {
+			t.Fatalf("RemovePluginConfig(%s) = %v, %v", name, removed, err)
+		}
+	}
+
+	decoded, err := DecodePluginConfigs([]byte("Crashy:\n  size: 3\nGone:\n  on: true\n"))
+	if err != nil {
+		t.Fatalf("DecodePluginConfigs() failed: %v", err)
+	}
+	names, err := ImportPluginConfigs(decoded)
+	if err != nil {
+		t.Fatalf("ImportPluginConfigs() failed: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"Crashy", "Gone"}) {
+		t.Errorf("ImportPluginConfigs() names = %v", names)
+	}
+
+	config, err := ReadPluginConfig("Gone")
+	if err != nil || config["on"] != true {
+		t.Errorf("ReadPluginConfig(Gone) = %v, %v after import", config, err)
+	}
+
+	if _, err := ImportPluginConfigs(map[string]PluginConfig{"../escape": {}}); err == nil {
+		t.Error("ImportPluginConfigs() should reject names that leave the plugins folder")
+	}
+}
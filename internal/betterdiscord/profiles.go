@@ -0,0 +1,513 @@
+package betterdiscord
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/betterdiscord/cli/internal/dryrun"
+	"github.com/betterdiscord/cli/internal/models"
+)
+
+// Profile is a named set of addon files and the addons enabled for each
+// channel.
+type Profile struct {
+	Created time.Time                `json:"created"`
+	Plugins []string                 `json:"plugins"`
+	Themes  []string                 `json:"themes"`
+	States  map[string]ProfileStates `json:"states,omitempty"`
+}
+
+// ProfileStates is what a profile enables for one channel.
+type ProfileStates struct {
+	Plugins AddonStates `json:"plugins,omitempty"`
+	Themes  AddonStates `json:"themes,omitempty"`
+}
+
+// Profiles is every saved profile and which one is active.
+type Profiles struct {
+	Active   string              `json:"active,omitempty"`
+	Profiles map[string]*Profile `json:"profiles"`
+}
+
+// ProfileSwitch describes the files moved when switching profiles.
+type ProfileSwitch struct {
+	Profile  string   `json:"profile"`
+	Parked   []string `json:"parked"`
+	Restored []string `json:"restored"`
+	Missing  []string `json:"missing"`
+}
+
+// fileMove is an addon file moved while switching profiles, so the move can
+// be undone if the switch fails part way.
+type fileMove struct {
+	from string
+	to   string
+}
+
+// ProfilesDir returns the folder profiles and the addon files that are not
+// part of the active profile are kept in.
+func (i *BDInstall) ProfilesDir() string {
+	return filepath.Join(i.data, "profiles")
+}
+
+func (i *BDInstall) profilesFile() string {
+	return filepath.Join(i.ProfilesDir(), "profiles.json")
+}
+
+// inactiveDir returns where addon files of kind outside the active profile
+// are parked.
+func (i *BDInstall) inactiveDir(kind AddonKind) string {
+	return filepath.Join(i.ProfilesDir(), "inactive", string(kind)+"s")
+}
+
+func (i *BDInstall) liveDir(kind AddonKind) string {
+	if kind == AddonTheme {
+		return i.themes
+	}
+	return i.plugins
+}
+
+// ReadProfiles reads the saved profiles. A missing file means none were
+// created yet.
+func (i *BDInstall) ReadProfiles() (*Profiles, error) {
+	profiles := &Profiles{Profiles: map[string]*Profile{}}
+	contents, err := os.ReadFile(i.profilesFile())
+	if os.IsNotExist(err) {
+		return profiles, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(contents, profiles); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", i.profilesFile(), err)
+	}
+	if profiles.Profiles == nil {
+		profiles.Profiles = map[string]*Profile{}
+	}
+	return profiles, nil
+}
+
+func (i *BDInstall) writeProfiles(profiles *Profiles) error {
+	path := i.profilesFile()
+	contents, err := json.MarshalIndent(profiles, "", "    ")
+	if err != nil {
+		return err
+	}
+	if dryrun.Skip(dryrun.WriteFile, path, "addon profiles") {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, contents, 0644)
+}
+
+// ProfileSelection limits a new profile to some of the installed addons.
+type ProfileSelection struct {
+	Plugins []string
+	Themes  []string
+}
+
+// Empty reports whether nothing was selected, meaning every addon is kept.
+func (s ProfileSelection) Empty() bool {
+	return len(s.Plugins) == 0 && len(s.Themes) == 0
+}
+
+// CreateProfile saves the addons enabled for every channel and the installed
+// addons as the profile name, or only the selected addons when selection is
+// not empty. A profile of everything becomes the active profile. An existing
+// profile is only replaced when overwrite is set.
+func (i *BDInstall) CreateProfile(name string, selection ProfileSelection, overwrite bool) (*Profile, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("profile name cannot be empty")
+	}
+
+	profiles, err := i.ReadProfiles()
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := profiles.Profiles[name]; exists && !overwrite {
+		return nil, fmt.Errorf("profile %s already exists", name)
+	}
+
+	profile, err := i.captureProfile()
+	if err != nil {
+		return nil, err
+	}
+	if !selection.Empty() {
+		if profile.Plugins, err = i.selectAddons(AddonPlugin, selection.Plugins); err != nil {
+			return nil, err
+		}
+		if profile.Themes, err = i.selectAddons(AddonTheme, selection.Themes); err != nil {
+			return nil, err
+		}
+	}
+
+	profiles.Profiles[name] = profile
+	if selection.Empty() || profiles.Active == name {
+		profiles.Active = name
+	}
+	if err := i.writeProfiles(profiles); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// UseProfile switches to the profile name. The current addons are saved to
+// the active profile first, addon files that are not part of name are parked
+// in the profile store, parked files it needs are moved back, and the
+// enabled addons it recorded are written for every channel. If any step
+// fails, the files already moved and the states already written are put
+// back, so the active profile always matches the addon folders.
+func (i *BDInstall) UseProfile(name string) (*ProfileSwitch, error) {
+	profiles, err := i.ReadProfiles()
+	if err != nil {
+		return nil, err
+	}
+	profile, ok := profiles.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %s not found", name)
+	}
+
+	current, err := i.captureProfile()
+	if err != nil {
+		return nil, err
+	}
+	if active := profiles.Profiles[profiles.Active]; active != nil {
+		current.Created = active.Created
+		profiles.Profiles[profiles.Active] = current
+		profile = profiles.Profiles[name]
+	} else if !containsAll(profile.Plugins, current.Plugins) || !containsAll(profile.Themes, current.Themes) || !sameStates(profile.States, current.States) {
+		// Without an active profile nothing records the current addons or
+		// enabled states, so switching would lose them
+		return nil, fmt.Errorf("the current addons are not saved in a profile, save them first with 'bdcli profile create <name>'")
+	}
+
+	var moves []fileMove
+	var previousStates map[string]ProfileStates
+	fail := func(err error) (*ProfileSwitch, error) {
+		if undoErr := i.undoSwitch(moves, previousStates); undoErr != nil {
+			return nil, fmt.Errorf("%w (undoing the switch also failed: %v)", err, undoErr)
+		}
+		return nil, err
+	}
+
+	result := &ProfileSwitch{Profile: name, Parked: []string{}, Restored: []string{}, Missing: []string{}}
+	for _, kind := range []AddonKind{AddonPlugin, AddonTheme} {
+		wanted := profile.Plugins
+		if kind == AddonTheme {
+			wanted = profile.Themes
+		}
+		if err := i.switchAddons(kind, wanted, result, &moves); err != nil {
+			return fail(err)
+		}
+	}
+
+	previousStates = current.States
+	for _, channel := range models.Channels {
+		states := profile.States[channel.String()]
+		if err := i.restoreStates(AddonPlugin, channel, states.Plugins); err != nil {
+			return fail(err)
+		}
+		if err := i.restoreStates(AddonTheme, channel, states.Themes); err != nil {
+			return fail(err)
+		}
+	}
+
+	profiles.Active = name
+	if err := i.writeProfiles(profiles); err != nil {
+		return fail(err)
+	}
+	return result, nil
+}
+
+// undoSwitch moves the files of a failed profile switch back, newest first,
+// and writes states back for every channel when it is not nil.
+func (i *BDInstall) undoSwitch(moves []fileMove, states map[string]ProfileStates) error {
+	var failed []string
+	for index := len(moves) - 1; index >= 0; index-- {
+		if err := os.Rename(moves[index].to, moves[index].from); err != nil {
+			failed = append(failed, filepath.Base(moves[index].from))
+		}
+	}
+	if states != nil {
+		for _, channel := range models.Channels {
+			previous := states[channel.String()]
+			if err := i.restoreStates(AddonPlugin, channel, previous.Plugins); err != nil {
+				failed = append(failed, i.AddonStatesPath(AddonPlugin, channel))
+			}
+			if err := i.restoreStates(AddonTheme, channel, previous.Themes); err != nil {
+				failed = append(failed, i.AddonStatesPath(AddonTheme, channel))
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("could not restore %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// DeleteProfile removes the profile name. Parked addon files that no other
+// profile uses are moved back into the addon folders, so nothing is lost;
+// their enabled state is left alone. It returns the files moved back.
+func (i *BDInstall) DeleteProfile(name string) ([]string, error) {
+	profiles, err := i.ReadProfiles()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := profiles.Profiles[name]; !ok {
+		return nil, fmt.Errorf("profile %s not found", name)
+	}
+	delete(profiles.Profiles, name)
+	if profiles.Active == name {
+		profiles.Active = ""
+	}
+
+	restored := []string{}
+	for _, kind := range []AddonKind{AddonPlugin, AddonTheme} {
+		used := map[string]bool{}
+		for _, profile := range profiles.Profiles {
+			files := profile.Plugins
+			if kind == AddonTheme {
+				files = profile.Themes
+			}
+			for _, file := range files {
+				used[strings.ToLower(file)] = true
+			}
+		}
+
+		for _, file := range i.parkedFiles(kind) {
+			if used[strings.ToLower(file)] {
+				continue
+			}
+			moved, err := i.unpark(kind, file, nil)
+			if err != nil {
+				return nil, err
+			}
+			if moved {
+				restored = append(restored, file)
+			}
+		}
+	}
+
+	if err := i.writeProfiles(profiles); err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+// captureProfile records the installed addons and enabled states.
+func (i *BDInstall) captureProfile() (*Profile, error) {
+	profile := &Profile{Created: time.Now().UTC(), States: map[string]ProfileStates{}}
+
+	for _, kind := range []AddonKind{AddonPlugin, AddonTheme} {
+		items, err := i.ListAddons(kind)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		files := []string{}
+		for _, item := range items {
+			files = append(files, item.FullFilename)
+		}
+		slices.Sort(files)
+		if kind == AddonPlugin {
+			profile.Plugins = files
+		} else {
+			profile.Themes = files
+		}
+	}
+
+	for _, channel := range models.Channels {
+		plugins, err := i.ReadAddonStates(AddonPlugin, channel)
+		if err != nil {
+			return nil, err
+		}
+		themes, err := i.ReadAddonStates(AddonTheme, channel)
+		if err != nil {
+			return nil, err
+		}
+		if len(plugins) > 0 || len(themes) > 0 {
+			profile.States[channel.String()] = ProfileStates{Plugins: plugins, Themes: themes}
+		}
+	}
+	return profile, nil
+}
+
+// selectAddons returns the filenames of the installed addons of kind that
+// match identifiers.
+func (i *BDInstall) selectAddons(kind AddonKind, identifiers []string) ([]string, error) {
+	items, err := i.ListAddons(kind)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	files := []string{}
+	for _, identifier := range identifiers {
+		entry := matchAddon(items, identifier)
+		if entry == nil {
+			return nil, fmt.Errorf("%s %s not found", kind, identifier)
+		}
+		if !slices.Contains(files, entry.FullFilename) {
+			files = append(files, entry.FullFilename)
+		}
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+// containsAll reports whether every file in subset is in files, ignoring case.
+func containsAll(files, subset []string) bool {
+	for _, file := range subset {
+		if !slices.ContainsFunc(files, func(f string) bool { return strings.EqualFold(f, file) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameStates reports whether a and b enable the same addons for every channel.
+func sameStates(a, b map[string]ProfileStates) bool {
+	equal := func(x, y AddonStates) bool {
+		return maps.EqualFunc(x, y, func(v, w json.RawMessage) bool {
+			var cv, cw bytes.Buffer
+			return json.Compact(&cv, v) == nil && json.Compact(&cw, w) == nil && bytes.Equal(cv.Bytes(), cw.Bytes())
+		})
+	}
+	for _, channel := range models.Channels {
+		x, y := a[channel.String()], b[channel.String()]
+		if !equal(x.Plugins, y.Plugins) || !equal(x.Themes, y.Themes) {
+			return false
+		}
+	}
+	return true
+}
+
+// switchAddons parks installed files of kind that are not wanted and moves
+// wanted files back from the profile store, recording each move in moves.
+func (i *BDInstall) switchAddons(kind AddonKind, wanted []string, result *ProfileSwitch, moves *[]fileMove) error {
+	items, err := i.ListAddons(kind)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	keep := map[string]bool{}
+	for _, file := range wanted {
+		if filepath.Base(file) != file || !isAddonFile(kind, file) {
+			return fmt.Errorf("invalid %s filename in profile: %q", kind, file)
+		}
+		keep[strings.ToLower(file)] = true
+	}
+
+	installed := map[string]bool{}
+	for _, item := range items {
+		installed[strings.ToLower(item.FullFilename)] = true
+		if keep[strings.ToLower(item.FullFilename)] {
+			continue
+		}
+		if err := i.park(kind, item.FullFilename, moves); err != nil {
+			return err
+		}
+		result.Parked = append(result.Parked, item.FullFilename)
+	}
+
+	for _, file := range wanted {
+		if installed[strings.ToLower(file)] {
+			continue
+		}
+		moved, err := i.unpark(kind, file, moves)
+		if err != nil {
+			return err
+		}
+		if moved {
+			result.Restored = append(result.Restored, file)
+		} else {
+			result.Missing = append(result.Missing, file)
+		}
+	}
+	return nil
+}
+
+// restoreStates writes the recorded enabled addons of kind for channel. A
+// channel without a states file and nothing recorded is left untouched.
+func (i *BDInstall) restoreStates(kind AddonKind, channel models.DiscordChannel, states AddonStates) error {
+	if states == nil {
+		if _, err := os.Stat(i.AddonStatesPath(kind, channel)); os.IsNotExist(err) {
+			return nil
+		}
+		states = AddonStates{}
+	}
+	return i.WriteAddonStates(kind, channel, maps.Clone(states))
+}
+
+// park moves an addon file into the profile store, adding the move to moves
+// when it is not nil. A parked file with the same name is never replaced.
+func (i *BDInstall) park(kind AddonKind, file string, moves *[]fileMove) error {
+	dir := i.inactiveDir(kind)
+	src := filepath.Join(i.liveDir(kind), file)
+	dest := filepath.Join(dir, file)
+	if _, err := os.Lstat(dest); err == nil {
+		return fmt.Errorf("another copy of %s is already parked in %s, move or delete one of them first", file, dir)
+	}
+	if dryrun.Skip(dryrun.Move, src, "to "+dest) {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(src, dest); err != nil {
+		return err
+	}
+	if moves != nil {
+		*moves = append(*moves, fileMove{from: src, to: dest})
+	}
+	return nil
+}
+
+// unpark moves a parked file back into the addon folder and reports whether
+// it did, adding the move to moves when it is not nil. A file installed under
+// the same name since is never replaced.
+func (i *BDInstall) unpark(kind AddonKind, file string, moves *[]fileMove) (bool, error) {
+	src := filepath.Join(i.inactiveDir(kind), file)
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return false, nil
+	}
+	dest := filepath.Join(i.liveDir(kind), file)
+	if _, err := os.Stat(dest); err == nil {
+		return false, nil
+	}
+	if dryrun.Skip(dryrun.Move, src, "to "+dest) {
+		return true, nil
+	}
+	if err := os.MkdirAll(i.liveDir(kind), 0755); err != nil {
+		return false, err
+	}
+	if err := os.Rename(src, dest); err != nil {
+		return false, err
+	}
+	if moves != nil {
+		*moves = append(*moves, fileMove{from: src, to: dest})
+	}
+	return true, nil
+}
+
+// parkedFiles lists the addon files of kind in the profile store.
+func (i *BDInstall) parkedFiles(kind AddonKind) []string {
+	entries, err := os.ReadDir(i.inactiveDir(kind))
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && isAddonFile(kind, e.Name()) {
+			files = append(files, e.Name())
+		}
+	}
+	return files
+}
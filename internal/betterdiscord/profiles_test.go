@@ -0,0 +1,193 @@
+package betterdiscord
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/betterdiscord/cli/internal/models"
+)
+
+func setupProfileInstall(t *testing.T) *BDInstall {
+	t.Helper()
+	install := newTestInstall(t)
+	for _, name := range []string{"Crashy", "Daily"} {
+		os.WriteFile(filepath.Join(install.Plugins(), name+".plugin.js"), []byte(validPluginNamed(name)), 0644) //nolint:errcheck
+	}
+	os.WriteFile(filepath.Join(install.Themes(), "Dark.theme.css"), []byte("/**\n * @name Dark\n */\n"), 0644)           //nolint:errcheck
+	install.WriteAddonStates(AddonPlugin, models.Stable, AddonStates{"Crashy": []byte("true"), "Daily": []byte("true")}) //nolint:errcheck
+	return install
+}
+
+func installedFiles(t *testing.T, install *BDInstall, kind AddonKind) []string {
+	t.Helper()
+	items, err := install.ListAddons(kind)
+	if err != nil {
+		t.Fatalf("ListAddons(%s) failed: %v", kind, err)
+	}
+	var files []string
+	for _, item := range items {
+		files = append(files, item.FullFilename)
+	}
+	slices.Sort(files)
+	return files
+}
+
+func TestProfiles_Switch(t *testing.T) {
+	install := setupProfileInstall(t)
+
+	if _, err := install.CreateProfile("full", ProfileSelection{}, false); err != nil {
+		t.Fatalf("CreateProfile(full) failed: %v", err)
+	}
+	minimal, err := install.CreateProfile("minimal", ProfileSelection{Plugins: []string{"crashy"}}, false)
+	if err != nil {
+		t.Fatalf("CreateProfile(minimal) failed: %v", err)
+	}
+	if !slices.Equal(minimal.Plugins, []string{"Crashy.plugin.js"}) || len(minimal.Themes) != 0 {
+		t.Errorf("minimal = %v %v, expected only Crashy.plugin.js", minimal.Plugins, minimal.Themes)
+	}
+	if _, err := install.CreateProfile("full", ProfileSelection{}, false); err == nil {
+		t.Error("CreateProfile() should not replace an existing profile without overwrite")
+	}
+
+	result, err := install.UseProfile("minimal")
+	if err != nil {
+		t.Fatalf("UseProfile(minimal) failed: %v", err)
+	}
+	if !slices.Equal(result.Parked, []string{"Daily.plugin.js", "Dark.theme.css"}) {
+		t.Errorf("Parked = %v", result.Parked)
+	}
+	if files := installedFiles(t, install, AddonPlugin); !slices.Equal(files, []string{"Crashy.plugin.js"}) {
+		t.Errorf("plugins after switching to minimal = %v", files)
+	}
+
+	// Changes made while a profile is active are kept when switching away
+	install.SetAddonsEnabled(AddonPlugin, models.Stable, []string{"Crashy"}, false) //nolint:errcheck
+
+	result, err = install.UseProfile("full")
+	if err != nil {
+		t.Fatalf("UseProfile(full) failed: %v", err)
+	}
+	if !slices.Equal(result.Restored, []string{"Daily.plugin.js", "Dark.theme.css"}) || len(result.Missing) != 0 {
+		t.Errorf("Restored = %v, Missing = %v", result.Restored, result.Missing)
+	}
+	if files := installedFiles(t, install, AddonPlugin); !slices.Equal(files, []string{"Crashy.plugin.js", "Daily.plugin.js"}) {
+		t.Errorf("plugins after switching to full = %v", files)
+	}
+	states, _ := install.ReadAddonStates(AddonPlugin, models.Stable)
+	if !states.Enabled("Crashy") || !states.Enabled("Daily") {
+		t.Errorf("full states = %v, expected both plugins enabled", states)
+	}
+
+	if _, err := install.UseProfile("minimal"); err != nil {
+		t.Fatalf("UseProfile(minimal) failed: %v", err)
+	}
+	states, _ = install.ReadAddonStates(AddonPlugin, models.Stable)
+	if states.Enabled("Crashy") {
+		t.Error("minimal should keep Crashy disabled")
+	}
+
+	profiles, err := install.ReadProfiles()
+	if err != nil || profiles.Active != "minimal" {
+		t.Errorf("Active = %v, %v, expected minimal", profiles, err)
+	}
+}
+
+func TestProfiles_UseWithoutActive(t *testing.T) {
+	install := setupProfileInstall(t)
+
+	if _, err := install.CreateProfile("minimal", ProfileSelection{Plugins: []string{"Crashy"}}, false); err != nil {
+		t.Fatalf("CreateProfile() failed: %v", err)
+	}
+	if _, err := install.UseProfile("minimal"); err == nil {
+		t.Error("UseProfile() should refuse to park addons that no profile records")
+	}
+	if files := installedFiles(t, install, AddonPlugin); len(files) != 2 {
+		t.Errorf("plugins = %v, expected nothing to be moved", files)
+	}
+	if _, err := install.UseProfile("missing"); err == nil {
+		t.Error("UseProfile() should fail for an unknown profile")
+	}
+
+	// The files are all saved in full, but the enabled states changed since
+	if _, err := install.CreateProfile("full", ProfileSelection{Plugins: []string{"Crashy", "Daily"}, Themes: []string{"Dark"}}, false); err != nil {
+		t.Fatalf("CreateProfile(full) failed: %v", err)
+	}
+	install.SetAddonsEnabled(AddonPlugin, models.Stable, []string{"Crashy"}, false) //nolint:errcheck
+	if _, err := install.UseProfile("full"); err == nil {
+		t.Error("UseProfile() should refuse to overwrite enabled states that no profile records")
+	}
+	states, _ := install.ReadAddonStates(AddonPlugin, models.Stable)
+	if states.Enabled("Crashy") {
+		t.Error("UseProfile() overwrote the unsaved enabled states")
+	}
+
+	// Once nothing would be lost, switching goes ahead
+	install.SetAddonsEnabled(AddonPlugin, models.Stable, []string{"Crashy"}, true) //nolint:errcheck
+	if _, err := install.UseProfile("full"); err != nil {
+		t.Errorf("UseProfile() failed although the current addons match full: %v", err)
+	}
+}
+
+func TestProfiles_Delete(t *testing.T) {
+	install := setupProfileInstall(t)
+
+	install.CreateProfile("full", ProfileSelection{}, false)                               //nolint:errcheck
+	install.CreateProfile("minimal", ProfileSelection{Plugins: []string{"Crashy"}}, false) //nolint:errcheck
+	install.CreateProfile("themed", ProfileSelection{Themes: []string{"Dark"}}, false)     //nolint:errcheck
+	if _, err := install.UseProfile("minimal"); err != nil {
+		t.Fatalf("UseProfile() failed: %v", err)
+	}
+
+	// Daily is only used by full, Dark is still used by themed
+	restored, err := install.DeleteProfile("full")
+	if err != nil {
+		t.Fatalf("DeleteProfile() failed: %v", err)
+	}
+	if !slices.Equal(restored, []string{"Daily.plugin.js"}) {
+		t.Errorf("DeleteProfile() restored %v, expected Daily.plugin.js", restored)
+	}
+	if files := installedFiles(t, install, AddonTheme); len(files) != 0 {
+		t.Errorf("themes = %v, expected Dark to stay parked", files)
+	}
+
+	if _, err := install.DeleteProfile("full"); err == nil {
+		t.Error("DeleteProfile() should fail for an unknown profile")
+	}
+}
+
+func TestProfiles_UseRollsBack(t *testing.T) {
+	install := setupProfileInstall(t)
+
+	install.CreateProfile("full", ProfileSelection{}, false)                               //nolint:errcheck
+	install.CreateProfile("crashy", ProfileSelection{Plugins: []string{"Crashy"}}, false)  //nolint:errcheck
+	install.CreateProfile("minimal", ProfileSelection{Plugins: []string{"Crashy"}}, false) //nolint:errcheck
+	if _, err := install.UseProfile("minimal"); err != nil {
+		t.Fatalf("UseProfile(minimal) failed: %v", err)
+	}
+
+	// A new Daily is installed while the old one is parked, and Alpha is
+	// parked before Daily when switching away
+	newDaily := "/**\n * @name Daily\n * @version 2.0.0\n */\nmodule.exports = class {};\n"
+	os.WriteFile(filepath.Join(install.Plugins(), "Daily.plugin.js"), []byte(newDaily), 0644)                  //nolint:errcheck
+	os.WriteFile(filepath.Join(install.Plugins(), "Alpha.plugin.js"), []byte(validPluginNamed("Alpha")), 0644) //nolint:errcheck
+
+	if _, err := install.UseProfile("crashy"); err == nil {
+		t.Fatal("UseProfile() should refuse to replace a parked file")
+	}
+	if files := installedFiles(t, install, AddonPlugin); !slices.Equal(files, []string{"Alpha.plugin.js", "Crashy.plugin.js", "Daily.plugin.js"}) {
+		t.Errorf("plugins = %v, expected the failed switch to be undone", files)
+	}
+	if contents, _ := os.ReadFile(filepath.Join(install.Plugins(), "Daily.plugin.js")); string(contents) != newDaily {
+		t.Error("the installed Daily should be left alone")
+	}
+	if contents, _ := os.ReadFile(filepath.Join(install.inactiveDir(AddonPlugin), "Daily.plugin.js")); string(contents) != validPluginNamed("Daily") {
+		t.Error("the parked Daily should not be replaced")
+	}
+
+	profiles, err := install.ReadProfiles()
+	if err != nil || profiles.Active != "minimal" {
+		t.Errorf("Active = %v, %v, expected minimal", profiles, err)
+	}
+}
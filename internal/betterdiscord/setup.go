@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/betterdiscord/cli/internal/dryrun"
 	"github.com/betterdiscord/cli/internal/models"
 	"github.com/betterdiscord/cli/internal/output"
 	"github.com/betterdiscord/cli/internal/utils"
@@ -17,6 +18,10 @@ func makeDirectory(folder string) error {
 		return nil
 	}
 
+	if dryrun.Skip(dryrun.CreateDir, folder, "") {
+		return nil
+	}
+
 	if err := os.MkdirAll(folder, 0755); err != nil {
 		output.Printf("❌ Failed to create directory: %s\n", folder)
 		output.Printf("   %s\n", err.Error())
@@ -49,6 +54,10 @@ func (i *BDInstall) repair(channel models.DiscordChannel) error {
 		return nil
 	}
 
+	if dryrun.Skip(dryrun.Remove, pluginsJson, "disables all plugins for "+channel.Name()) {
+		return nil
+	}
+
 	if err := os.Remove(pluginsJson); err != nil {
 		output.Printf("❌ Unable to remove file %s\n", pluginsJson)
 		output.Printf("   %s\n", err.Error())
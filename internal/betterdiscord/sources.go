@@ -0,0 +1,525 @@
+package betterdiscord
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/betterdiscord/cli/internal/dryrun"
+	"github.com/betterdiscord/cli/internal/models"
+	"github.com/betterdiscord/cli/internal/utils"
+)
+
+// githubPrefix marks github:owner/repo[@ref][/path] addon sources.
+const githubPrefix = "github:"
+
+// maxArchiveAddonSize caps how much of a single archive entry is extracted.
+const maxArchiveAddonSize = 16 << 20
+
+// GitHub endpoints that github: sources are resolved against.
+var (
+	githubAPI = "https://api.github.com"
+	githubRaw = "https://raw.githubusercontent.com"
+)
+
+// InstallOptions changes how InstallAddonWith installs an addon.
+type InstallOptions struct {
+	// Link symlinks a local addon file instead of copying it, so edits to
+	// the original are picked up directly.
+	Link bool
+}
+
+// GitHubSource is a parsed github:owner/repo[@ref][/path] source.
+type GitHubSource struct {
+	Owner string
+	Repo  string
+	Ref   string
+	Path  string
+}
+
+// ParseGitHubSource parses a github: source. ok is false when identifier is
+// not a github: source at all. The ref ends at the first "/", so refs that
+// contain one, such as feature/x, cannot be given. A path must name an addon
+// file or a .zip archive, which also rejects most such refs: the rest of the
+// ref would be read as part of the path.
+func ParseGitHubSource(identifier string) (source *GitHubSource, ok bool, err error) {
+	rest, ok := strings.CutPrefix(identifier, githubPrefix)
+	if !ok {
+		return nil, false, nil
+	}
+
+	parts := strings.SplitN(rest, "/", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return nil, true, fmt.Errorf("invalid GitHub source %q, expected github:owner/repo[@ref][/path]", identifier)
+	}
+
+	source = &GitHubSource{Owner: parts[0], Repo: parts[1]}
+	if repo, ref, hasRef := strings.Cut(parts[1], "@"); hasRef {
+		if repo == "" || ref == "" {
+			return nil, true, fmt.Errorf("invalid GitHub source %q, expected github:owner/repo[@ref][/path]", identifier)
+		}
+		source.Repo, source.Ref = repo, ref
+	}
+	if len(parts) == 3 {
+		source.Path = strings.Trim(parts[2], "/")
+	}
+	if source.Path != "" && !isArchive(source.Path) && !isAddonFile(AddonPlugin, source.Path) && !isAddonFile(AddonTheme, source.Path) {
+		if source.Ref != "" {
+			return nil, true, fmt.Errorf("invalid GitHub source %q: %s is not a .plugin.js, .theme.css, or .zip file (refs cannot contain \"/\", so the ref was read as %q)", identifier, source.Path, source.Ref)
+		}
+		return nil, true, fmt.Errorf("invalid GitHub source %q: %s is not a .plugin.js, .theme.css, or .zip file", identifier, source.Path)
+	}
+	return source, true, nil
+}
+
+// IsAddonSource reports whether identifier names where to get an addon from
+// (a URL, a github: source, or a local file) rather than a store addon.
+func IsAddonSource(identifier string) bool {
+	return utils.IsURL(identifier) || strings.HasPrefix(identifier, githubPrefix) || isLocalSource(identifier)
+}
+
+// InstallAddonWith installs an addon like InstallAddon, and also accepts local
+// files, github:owner/repo[@ref][/path] sources, and .zip archives holding one
+// or more addons.
+func InstallAddonWith(kind AddonKind, identifier string, options InstallOptions) (*ResolvedAddon, error) {
+	dir, err := addonDir(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Link && (!isLocalSource(identifier) || isArchive(identifier)) {
+		return nil, fmt.Errorf("only local addon files can be linked")
+	}
+
+	if source, ok, err := ParseGitHubSource(identifier); ok {
+		if err != nil {
+			return nil, err
+		}
+		return installGitHub(kind, dir, source)
+	}
+
+	if isLocalSource(identifier) {
+		return installLocal(kind, dir, identifier, options)
+	}
+
+	if utils.IsURL(identifier) && isArchive(urlPath(identifier)) {
+		return installArchiveURL(kind, dir, identifier)
+	}
+
+	return InstallAddon(kind, identifier)
+}
+
+// isLocalSource reports whether identifier is an existing file that looks like
+// a path, so store names are never mistaken for files in the working directory.
+func isLocalSource(identifier string) bool {
+	if utils.IsURL(identifier) || strings.HasPrefix(identifier, githubPrefix) {
+		return false
+	}
+	info, err := os.Stat(identifier)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	return strings.ContainsAny(identifier, `/\`) || isArchive(identifier) || isAddonFile(AddonPlugin, identifier) || isAddonFile(AddonTheme, identifier)
+}
+
+func isArchive(name string) bool {
+	return strings.HasSuffix(strings.ToLower(name), ".zip")
+}
+
+func urlPath(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Path
+}
+
+// checkAddon makes sure contents is an addon of kind called name with at
+// least a @name in its header.
+func checkAddon(kind AddonKind, name string, contents []byte) error {
+	if !isAddonFile(kind, name) {
+		return fmt.Errorf("%s is not a %s file", name, kind)
+	}
+	if parseJSDoc(string(contents)).Name == "" {
+		return fmt.Errorf("%s is not a valid %s (missing @name)", name, kind)
+	}
+	return nil
+}
+
+func installLocal(kind AddonKind, dir, src string, options InstallOptions) (*ResolvedAddon, error) {
+	abs, err := filepath.Abs(src)
+	if err != nil {
+		return nil, err
+	}
+	if isArchive(abs) {
+		paths, err := installArchive(kind, dir, abs, abs)
+		return resolvedPaths(paths), err
+	}
+
+	contents, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAddon(kind, filepath.Base(abs), contents); err != nil {
+		return nil, err
+	}
+	dest, err := addonPath(kind, dir, filepath.Base(abs))
+	if err != nil {
+		return nil, err
+	}
+
+	if info, err := os.Stat(dest); err == nil {
+		if srcInfo, err := os.Stat(abs); err == nil && os.SameFile(info, srcInfo) {
+			return nil, fmt.Errorf("%s is already installed in the %ss folder", filepath.Base(abs), kind)
+		}
+	}
+
+	if options.Link {
+		if dryrun.Skip(dryrun.WriteFile, dest, "link to "+abs) {
+			return resolvedPaths([]string{dest}), nil
+		}
+		if err := linkAddon(kind, dest, abs); err != nil {
+			return nil, fmt.Errorf("failed to link %s: %w", abs, err)
+		}
+		return resolvedPaths([]string{dest}), nil
+	}
+
+	if dryrun.Skip(dryrun.WriteFile, dest, "copy of "+abs) {
+		return resolvedPaths([]string{dest}), nil
+	}
+	if err := writeAddon(kind, dest, contents); err != nil {
+		return nil, err
+	}
+	return resolvedPaths([]string{dest}), nil
+}
+
+func installGitHub(kind AddonKind, dir string, source *GitHubSource) (*ResolvedAddon, error) {
+	if source.Path != "" {
+		ref := source.Ref
+		if ref == "" {
+			ref = "HEAD"
+		}
+		rawURL := fmt.Sprintf("%s/%s/%s/%s/%s", githubRaw, source.Owner, source.Repo, ref, source.Path)
+		if isArchive(source.Path) {
+			return installArchiveURL(kind, dir, rawURL)
+		}
+		dest, err := downloadCheckedAddon(kind, dir, path.Base(source.Path), rawURL)
+		if err != nil && source.Ref != "" {
+			return nil, fmt.Errorf("%w (refs cannot contain \"/\", so the ref was read as %q and the path as %s)", err, source.Ref, source.Path)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return resolvedPaths([]string{dest}), nil
+	}
+
+	releaseURL := fmt.Sprintf("%s/repos/%s/%s/releases/latest", githubAPI, source.Owner, source.Repo)
+	if source.Ref != "" {
+		releaseURL = fmt.Sprintf("%s/repos/%s/%s/releases/tags/%s", githubAPI, source.Owner, source.Repo, url.PathEscape(source.Ref))
+	}
+	release, err := utils.DownloadJSON[models.GitHubRelease](releaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find a release of %s/%s: %w", source.Owner, source.Repo, err)
+	}
+
+	var assets []releaseAsset
+	archive := ""
+	for _, asset := range release.Assets {
+		if isAddonFile(kind, asset.Name) {
+			assets = append(assets, releaseAsset{name: asset.Name, url: asset.BrowserDownloadURL})
+		} else if archive == "" && isArchive(asset.Name) {
+			archive = asset.BrowserDownloadURL
+		}
+	}
+	if len(assets) > 0 {
+		paths, err := installAssets(kind, dir, assets)
+		return resolvedPaths(paths), err
+	}
+	if archive != "" {
+		return installArchiveURL(kind, dir, archive)
+	}
+	return nil, fmt.Errorf("release %s of %s/%s has no %s files or .zip archives", release.TagName, source.Owner, source.Repo, kind)
+}
+
+// downloadCheckedAddon downloads rawURL as the addon file name, only
+// replacing an installed file once the download checks out.
+func downloadCheckedAddon(kind AddonKind, dir, name, rawURL string) (string, error) {
+	if !isAddonFile(kind, name) {
+		return "", fmt.Errorf("%s is not a %s file", name, kind)
+	}
+	dest, err := addonPath(kind, dir, name)
+	if err != nil {
+		return "", err
+	}
+	if dryrun.Skip(dryrun.Download, dest, "from "+rawURL) {
+		return dest, nil
+	}
+
+	hadPrevious := false
+	_, err = utils.DownloadFileVerified(rawURL, dest, func(tmpPath string) error {
+		contents, err := os.ReadFile(tmpPath)
+		if err != nil {
+			return err
+		}
+		if err := checkAddon(kind, name, contents); err != nil {
+			return err
+		}
+		hadPrevious, err = backupAddon(dest)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	if hadPrevious {
+		recordReplaced(kind, dest)
+	}
+	return dest, nil
+}
+
+// releaseAsset is an addon file attached to a GitHub release.
+type releaseAsset struct {
+	name string
+	url  string
+}
+
+// installAssets downloads and checks every release asset before any of
+// them replaces an installed file.
+func installAssets(kind AddonKind, dir string, assets []releaseAsset) ([]string, error) {
+	tmp, err := os.MkdirTemp("", "bdcli-release-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp) //nolint:errcheck
+
+	var staged []stagedAddon
+	var planned []string
+	for index, asset := range assets {
+		dest, err := addonPath(kind, dir, asset.name)
+		if err != nil {
+			return nil, err
+		}
+		if dryrun.Skip(dryrun.Download, dest, "from "+asset.url) {
+			planned = append(planned, dest)
+			continue
+		}
+
+		file := filepath.Join(tmp, fmt.Sprintf("%d.download", index))
+		if _, err := utils.DownloadFile(asset.url, file); err != nil {
+			return nil, fmt.Errorf("failed to download %s: %w", asset.name, err)
+		}
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkAddon(kind, asset.name, contents); err != nil {
+			return nil, err
+		}
+		staged = append(staged, stagedAddon{dest: dest, contents: contents})
+	}
+	if len(planned) > 0 {
+		return planned, nil
+	}
+	return writeAddons(kind, staged)
+}
+
+func installArchiveURL(kind AddonKind, dir, rawURL string) (*ResolvedAddon, error) {
+	if dryrun.Skip(dryrun.Download, dir, fmt.Sprintf("%ss from %s", kind, rawURL)) {
+		return &ResolvedAddon{}, nil
+	}
+
+	tmp, err := os.MkdirTemp("", "bdcli-archive-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp) //nolint:errcheck
+
+	archive := filepath.Join(tmp, "addons.zip")
+	if _, err := utils.DownloadFile(rawURL, archive); err != nil {
+		return nil, err
+	}
+	paths, err := installArchive(kind, dir, archive, rawURL)
+	return resolvedPaths(paths), err
+}
+
+// installArchive extracts every addon of kind from the zip archive into dir.
+// All of them are checked before anything is written, and the files already
+// written are returned along with any error.
+func installArchive(kind AddonKind, dir, archive, source string) ([]string, error) {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a valid .zip archive: %w", source, err)
+	}
+	defer r.Close() //nolint:errcheck
+
+	var found []stagedAddon
+	seen := map[string]bool{}
+
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") {
+			continue
+		}
+		name := path.Base(f.Name)
+		if !isAddonFile(kind, name) {
+			continue
+		}
+		if !safeArchiveName(f.Name) {
+			return nil, fmt.Errorf("archive entry %q has an unsafe path", f.Name)
+		}
+		if f.UncompressedSize64 > maxArchiveAddonSize {
+			return nil, fmt.Errorf("archive entry %s is too large", f.Name)
+		}
+
+		contents, err := readArchiveEntry(f)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkAddon(kind, name, contents); err != nil {
+			return nil, err
+		}
+
+		dest, err := addonPath(kind, dir, name)
+		if err != nil {
+			return nil, err
+		}
+		if seen[strings.ToLower(dest)] {
+			return nil, fmt.Errorf("archive contains %s more than once", name)
+		}
+		seen[strings.ToLower(dest)] = true
+		found = append(found, stagedAddon{dest: dest, contents: contents})
+	}
+
+	if len(found) == 0 {
+		return nil, fmt.Errorf("no %s files found in %s", kind, source)
+	}
+
+	var planned []string
+	for _, item := range found {
+		if dryrun.Skip(dryrun.WriteFile, item.dest, "from "+source) {
+			planned = append(planned, item.dest)
+		}
+	}
+	if len(planned) > 0 {
+		return planned, nil
+	}
+	return writeAddons(kind, found)
+}
+
+// stagedAddon is a checked addon file waiting to be written to dest.
+type stagedAddon struct {
+	dest     string
+	contents []byte
+}
+
+// writeAddons writes every staged addon with writeAddon. On failure it
+// returns the paths already written along with the error, so they can be
+// reported.
+func writeAddons(kind AddonKind, staged []stagedAddon) ([]string, error) {
+	var paths []string
+	for _, item := range staged {
+		if err := writeAddon(kind, item.dest, item.contents); err != nil {
+			if len(paths) > 0 {
+				err = fmt.Errorf("failed to install %s after installing %d other file(s): %w", filepath.Base(item.dest), len(paths), err)
+			}
+			return paths, err
+		}
+		paths = append(paths, item.dest)
+	}
+	return paths, nil
+}
+
+func readArchiveEntry(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close() //nolint:errcheck
+
+	contents, err := io.ReadAll(io.LimitReader(rc, maxArchiveAddonSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract %s: %w", f.Name, err)
+	}
+	if len(contents) > maxArchiveAddonSize {
+		return nil, fmt.Errorf("archive entry %s is too large", f.Name)
+	}
+	return contents, nil
+}
+
+// safeArchiveName rejects entries that point outside the archive, even
+// though only their base name is used.
+func safeArchiveName(name string) bool {
+	if strings.ContainsAny(name, `\:`) || path.IsAbs(name) {
+		return false
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// writeAddon atomically replaces dest with contents. A replaced addon file is
+// kept as dest.bak and recorded in its history, like an update.
+func writeAddon(kind AddonKind, dest string, contents []byte) error {
+	hadPrevious, err := backupAddon(dest)
+	if err != nil {
+		return err
+	}
+	if err := utils.WriteFileAtomic(dest, contents, 0644); err != nil {
+		return err
+	}
+	if hadPrevious {
+		recordReplaced(kind, dest)
+	}
+	return nil
+}
+
+// linkAddon atomically replaces dest with a symlink to target, keeping a
+// replaced addon file like writeAddon does.
+func linkAddon(kind AddonKind, dest, target string) error {
+	hadPrevious, err := backupAddon(dest)
+	if err != nil {
+		return err
+	}
+
+	tmp := fmt.Sprintf("%s.%d.link", dest, time.Now().UnixNano())
+	if err := os.Symlink(target, tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		os.Remove(tmp) //nolint:errcheck
+		return err
+	}
+	if hadPrevious {
+		recordReplaced(kind, dest)
+	}
+	return nil
+}
+
+// backupAddon copies the addon file at dest to dest.bak before it is
+// replaced, and reports whether there was one. Symlinks are not kept, since
+// the file they point at stays where it is.
+func backupAddon(dest string) (bool, error) {
+	info, err := os.Lstat(dest)
+	if err != nil || !info.Mode().IsRegular() {
+		return false, nil
+	}
+	if err := utils.CopyFile(dest, dest+".bak"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func resolvedPaths(paths []string) *ResolvedAddon {
+	resolved := &ResolvedAddon{Paths: paths}
+	if len(paths) > 0 {
+		resolved.Path = paths[0]
+	}
+	return resolved
+}
@@ -0,0 +1,333 @@
+package betterdiscord
+
+import (
+	"archive/zip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeZip(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(f)
+	for name, contents := range files {
+		entry, _ := w.Create(name)
+		entry.Write([]byte(contents)) //nolint:errcheck
+	}
+	w.Close() //nolint:errcheck
+	f.Close() //nolint:errcheck
+}
+
+func TestParseGitHubSource(t *testing.T) {
+	tests := []struct {
+		identifier string
+		ok         bool
+		wantErr    bool
+		expected   GitHubSource
+	}{
+		{"github:owner/repo", true, false, GitHubSource{Owner: "owner", Repo: "repo"}},
+		{"github:owner/repo@v1.2.0", true, false, GitHubSource{Owner: "owner", Repo: "repo", Ref: "v1.2.0"}},
+		{"github:owner/repo@main/dist/My.plugin.js", true, false, GitHubSource{Owner: "owner", Repo: "repo", Ref: "main", Path: "dist/My.plugin.js"}},
+		{"github:owner/repo/My.plugin.js", true, false, GitHubSource{Owner: "owner", Repo: "repo", Path: "My.plugin.js"}},
+		{"github:owner/repo@main/Addons.zip", true, false, GitHubSource{Owner: "owner", Repo: "repo", Ref: "main", Path: "Addons.zip"}},
+		// Refs cannot contain "/", and a path must name an addon file
+		{"github:owner/repo@feature/x", true, true, GitHubSource{}},
+		{"github:owner/repo/dist", true, true, GitHubSource{}},
+		{"github:owner", true, true, GitHubSource{}},
+		{"github:owner/repo@", true, true, GitHubSource{}},
+		{"owner/repo", false, false, GitHubSource{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.identifier, func(t *testing.T) {
+			source, ok, err := ParseGitHubSource(tt.identifier)
+			if ok != tt.ok {
+				t.Fatalf("ParseGitHubSource() ok = %v, expected %v", ok, tt.ok)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseGitHubSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if source != nil && *source != tt.expected {
+				t.Errorf("ParseGitHubSource() = %+v, expected %+v", *source, tt.expected)
+			}
+		})
+	}
+}
+
+func TestInstallAddonWith_Local(t *testing.T) {
+	install := newTestInstall(t)
+	src := filepath.Join(t.TempDir(), "Local.plugin.js")
+	os.WriteFile(src, []byte(validPluginNamed("Local")), 0644) //nolint:errcheck
+	dest := filepath.Join(install.Plugins(), "Local.plugin.js")
+
+	resolved, err := InstallAddonWith(AddonPlugin, src, InstallOptions{})
+	if err != nil {
+		t.Fatalf("InstallAddonWith() failed: %v", err)
+	}
+	if resolved.Path != dest {
+		t.Errorf("InstallAddonWith() path = %s, expected %s", resolved.Path, dest)
+	}
+	if info, err := os.Lstat(dest); err != nil || info.Mode()&os.ModeSymlink != 0 {
+		t.Fatalf("plugin should be copied, got %v, %v", info, err)
+	}
+
+	if _, err := InstallAddonWith(AddonPlugin, src, InstallOptions{Link: true}); err != nil {
+		t.Fatalf("InstallAddonWith() with link failed: %v", err)
+	}
+	if target, err := os.Readlink(dest); err != nil || target != src {
+		t.Errorf("plugin should link to %s, got %q, %v", src, target, err)
+	}
+
+	// The linked file is the original, so copying it would truncate it
+	if _, err := InstallAddonWith(AddonPlugin, src, InstallOptions{}); err == nil {
+		t.Error("InstallAddonWith() should refuse to install a file over itself")
+	}
+
+	if _, err := InstallAddonWith(AddonPlugin, "Local", InstallOptions{Link: true}); err == nil {
+		t.Error("InstallAddonWith() should only link local files")
+	}
+}
+
+func TestInstallAddonWith_ReplaceKeepsHistory(t *testing.T) {
+	install := newTestInstall(t)
+	dest := filepath.Join(install.Plugins(), "One.plugin.js")
+	os.WriteFile(dest, []byte(validPluginNamed("One")), 0644) //nolint:errcheck
+
+	src := filepath.Join(t.TempDir(), "One.plugin.js")
+	os.WriteFile(src, []byte("/**\n * @name One\n * @version 2.0.0\n */\n"), 0644) //nolint:errcheck
+	archive := filepath.Join(t.TempDir(), "pack.zip")
+	writeZip(t, archive, map[string]string{"One.plugin.js": "/**\n * @name One\n * @version 3.0.0\n */\n"})
+
+	for i, source := range []string{src, archive} {
+		if _, err := InstallAddonWith(AddonPlugin, source, InstallOptions{}); err != nil {
+			t.Fatalf("InstallAddonWith(%s) failed: %v", source, err)
+		}
+		dir, _ := historyDir(AddonPlugin, "One.plugin.js")
+		entries, err := readHistory(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != i+1 {
+			t.Fatalf("history has %d entries after %s, expected %d", len(entries), source, i+1)
+		}
+		if _, err := os.Stat(dest + ".bak"); err != nil {
+			t.Errorf("replaced plugin should be kept as .bak: %v", err)
+		}
+	}
+
+	// Linking replaces the file without writing through it
+	if _, err := InstallAddonWith(AddonPlugin, src, InstallOptions{Link: true}); err != nil {
+		t.Fatalf("InstallAddonWith() with link failed: %v", err)
+	}
+	backup, _ := os.ReadFile(dest + ".bak")
+	if parseJSDoc(string(backup)).Version != "3.0.0" {
+		t.Errorf(".bak should hold the replaced 3.0.0 plugin, got %q", backup)
+	}
+	if target, err := os.Readlink(dest); err != nil || target != src {
+		t.Errorf("plugin should link to %s, got %q, %v", src, target, err)
+	}
+}
+
+func TestInstallAddonWith_LocalInvalid(t *testing.T) {
+	newTestInstall(t)
+	dir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		file     string
+		contents string
+	}{
+		{"missing @name", "Broken.plugin.js", "module.exports = class {};"},
+		{"wrong kind", "Style.theme.css", "/**\n * @name Style\n */\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := filepath.Join(dir, tt.file)
+			os.WriteFile(src, []byte(tt.contents), 0644) //nolint:errcheck
+			if _, err := InstallAddonWith(AddonPlugin, src, InstallOptions{}); err == nil {
+				t.Errorf("InstallAddonWith() should reject %s", tt.file)
+			}
+		})
+	}
+}
+
+func TestInstallAddonWith_Archive(t *testing.T) {
+	install := newTestInstall(t)
+	archive := filepath.Join(t.TempDir(), "pack.zip")
+	writeZip(t, archive, map[string]string{
+		"pack/One.plugin.js":            validPluginNamed("One"),
+		"pack/nested/Two.plugin.js":     validPluginNamed("Two"),
+		"pack/README.md":                "not an addon",
+		"__MACOSX/pack/._One.plugin.js": "resource fork",
+	})
+
+	resolved, err := InstallAddonWith(AddonPlugin, archive, InstallOptions{})
+	if err != nil {
+		t.Fatalf("InstallAddonWith() failed: %v", err)
+	}
+	if len(resolved.Paths) != 2 {
+		t.Fatalf("InstallAddonWith() paths = %v, expected 2 plugins", resolved.Paths)
+	}
+	for _, name := range []string{"One.plugin.js", "Two.plugin.js"} {
+		if _, err := os.Stat(filepath.Join(install.Plugins(), name)); err != nil {
+			t.Errorf("%s was not extracted: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(install.Plugins(), "README.md")); err == nil {
+		t.Error("files that are not plugins should not be extracted")
+	}
+}
+
+func TestInstallAddonWith_ArchiveRejected(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+	}{
+		{"path traversal", map[string]string{"Good.plugin.js": validPluginNamed("Good"), "../../Evil.plugin.js": validPluginNamed("Evil")}},
+		{"absolute path", map[string]string{"/tmp/Evil.plugin.js": validPluginNamed("Evil")}},
+		{"invalid addon", map[string]string{"Good.plugin.js": validPluginNamed("Good"), "Broken.plugin.js": "module.exports = 1;"}},
+		{"duplicate names", map[string]string{"a/Good.plugin.js": validPluginNamed("Good"), "b/Good.plugin.js": validPluginNamed("Good")}},
+		{"no addons", map[string]string{"README.md": "nothing here"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			install := newTestInstall(t)
+			archive := filepath.Join(t.TempDir(), "pack.zip")
+			writeZip(t, archive, tt.files)
+
+			if _, err := InstallAddonWith(AddonPlugin, archive, InstallOptions{}); err == nil {
+				t.Fatal("InstallAddonWith() should reject the archive")
+			}
+			entries, _ := os.ReadDir(install.Plugins())
+			if len(entries) != 0 {
+				t.Errorf("nothing should be written from a rejected archive, found %d file(s)", len(entries))
+			}
+		})
+	}
+}
+
+func TestInstallAddonWith_GitHub(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "themes.zip")
+	writeZip(t, archive, map[string]string{"Dark.theme.css": "/**\n * @name Dark\n */\n"})
+	archiveBytes, _ := os.ReadFile(archive)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/owner/repo/HEAD/src/Raw.plugin.js":
+			w.Write([]byte(validPluginNamed("Raw"))) //nolint:errcheck
+		case "/repos/owner/repo/releases/latest":
+			w.Write([]byte(`{"tag_name": "v1.0.0", "assets": [{"name": "Released.plugin.js", "browser_download_url": "http://` + r.Host + `/download/Released.plugin.js"}]}`)) //nolint:errcheck
+		case "/repos/owner/repo/releases/tags/v2":
+			w.Write([]byte(`{"tag_name": "v2", "assets": [{"name": "themes.zip", "browser_download_url": "http://` + r.Host + `/download/themes.zip"}]}`)) //nolint:errcheck
+		case "/download/Released.plugin.js":
+			w.Write([]byte(validPluginNamed("Released"))) //nolint:errcheck
+		case "/download/themes.zip":
+			w.Write(archiveBytes) //nolint:errcheck
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	oldAPI, oldRaw := githubAPI, githubRaw
+	githubAPI, githubRaw = server.URL, server.URL
+	t.Cleanup(func() { githubAPI, githubRaw = oldAPI, oldRaw })
+
+	tests := []struct {
+		kind       AddonKind
+		identifier string
+		expected   string
+	}{
+		{AddonPlugin, "github:owner/repo/src/Raw.plugin.js", "Raw.plugin.js"},
+		{AddonPlugin, "github:owner/repo", "Released.plugin.js"},
+		{AddonTheme, "github:owner/repo@v2", "Dark.theme.css"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.identifier, func(t *testing.T) {
+			newTestInstall(t)
+			dir, _ := addonDir(tt.kind)
+
+			resolved, err := InstallAddonWith(tt.kind, tt.identifier, InstallOptions{})
+			if err != nil {
+				t.Fatalf("InstallAddonWith() failed: %v", err)
+			}
+			if expected := filepath.Join(dir, tt.expected); resolved.Path != expected {
+				t.Errorf("InstallAddonWith() path = %s, expected %s", resolved.Path, expected)
+			}
+			if _, err := os.Stat(resolved.Path); err != nil {
+				t.Errorf("addon was not installed: %v", err)
+			}
+		})
+	}
+
+	newTestInstall(t)
+	if _, err := InstallAddonWith(AddonTheme, "github:owner/repo", InstallOptions{}); err == nil {
+		t.Error("InstallAddonWith() should fail when a release has no matching files")
+	}
+
+	// A ref with a "/" is read as a shorter ref, so the file is not found
+	_, err := InstallAddonWith(AddonPlugin, "github:owner/repo@feature/x/Raw.plugin.js", InstallOptions{})
+	if err == nil || !strings.Contains(err.Error(), "refs cannot contain") {
+		t.Errorf("InstallAddonWith() error = %v, expected it to explain that refs cannot contain \"/\"", err)
+	}
+}
+
+func TestInstallAddonWith_GitHubReleaseStaged(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/releases/latest":
+			w.Write([]byte(`{"tag_name": "v1.0.0", "assets": [` + //nolint:errcheck
+				`{"name": "Good.plugin.js", "browser_download_url": "http://` + r.Host + `/download/Good.plugin.js"},` +
+				`{"name": "Broken.plugin.js", "browser_download_url": "http://` + r.Host + `/download/Broken.plugin.js"}]}`))
+		case "/download/Good.plugin.js":
+			w.Write([]byte(validPluginNamed("Good"))) //nolint:errcheck
+		case "/download/Broken.plugin.js":
+			w.Write([]byte("module.exports = 1;")) //nolint:errcheck
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	oldAPI := githubAPI
+	githubAPI = server.URL
+	t.Cleanup(func() { githubAPI = oldAPI })
+
+	install := newTestInstall(t)
+	if _, err := InstallAddonWith(AddonPlugin, "github:owner/repo", InstallOptions{}); err == nil {
+		t.Fatal("InstallAddonWith() should reject a release with an invalid asset")
+	}
+	entries, _ := os.ReadDir(install.Plugins())
+	if len(entries) != 0 {
+		t.Errorf("nothing should be written from a rejected release, found %d file(s)", len(entries))
+	}
+}
+
+func TestWriteAddons_ReportsWritten(t *testing.T) {
+	install := newTestInstall(t)
+	first := filepath.Join(install.Plugins(), "First.plugin.js")
+	blocked := filepath.Join(install.Plugins(), "Blocked.plugin.js")
+	// A non-empty folder in the way makes the second write fail
+	os.MkdirAll(filepath.Join(blocked, "inside"), 0755) //nolint:errcheck
+
+	paths, err := writeAddons(AddonPlugin, []stagedAddon{
+		{dest: first, contents: []byte(validPluginNamed("First"))},
+		{dest: blocked, contents: []byte(validPluginNamed("Blocked"))},
+	})
+	if err == nil {
+		t.Fatal("writeAddons() should fail when a file cannot be written")
+	}
+	if len(paths) != 1 || paths[0] != first {
+		t.Errorf("writeAddons() paths = %v, expected only %s", paths, first)
+	}
+}
@@ -0,0 +1,170 @@
+package betterdiscord
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/betterdiscord/cli/internal/dryrun"
+	"github.com/betterdiscord/cli/internal/models"
+)
+
+// AddonStates maps addon names to whether BetterDiscord should load them, as
+// stored in data/<channel>/plugins.json and themes.json. Values are kept raw
+// so entries the CLI does not understand survive a rewrite untouched.
+type AddonStates map[string]json.RawMessage
+
+// Enabled reports whether the addon called name is switched on.
+func (s AddonStates) Enabled(name string) bool {
+	var enabled bool
+	return json.Unmarshal(s[name], &enabled) == nil && enabled
+}
+
+// Set switches the addon called name on or off.
+func (s AddonStates) Set(name string, enabled bool) {
+	s[name] = json.RawMessage(fmt.Sprint(enabled))
+}
+
+// key returns the entry matching name regardless of case, or name itself.
+func (s AddonStates) key(name string) string {
+	for key := range s {
+		if strings.EqualFold(key, name) {
+			return key
+		}
+	}
+	return name
+}
+
+// entryKey returns the entry an installed addon is recorded under: its
+// @name, or its base filename when it has none.
+func (s AddonStates) entryKey(entry *AddonEntry) string {
+	name := entry.Meta.Name
+	if name == "" {
+		name = entry.BaseName
+	}
+	return s.key(name)
+}
+
+// AddonStatesPath returns the file BetterDiscord records enabled addons of
+// kind in for channel.
+func (i *BDInstall) AddonStatesPath(kind AddonKind, channel models.DiscordChannel) string {
+	return filepath.Join(i.data, channel.String(), string(kind)+"s.json")
+}
+
+// ReadAddonStates reads the enabled addons of kind for channel. A missing
+// file means nothing has been enabled yet.
+func (i *BDInstall) ReadAddonStates(kind AddonKind, channel models.DiscordChannel) (AddonStates, error) {
+	path := i.AddonStatesPath(kind, channel)
+	contents, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return AddonStates{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	states := AddonStates{}
+	if err := json.Unmarshal(contents, &states); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return states, nil
+}
+
+// WriteAddonStates saves the enabled addons of kind for channel.
+func (i *BDInstall) WriteAddonStates(kind AddonKind, channel models.DiscordChannel, states AddonStates) error {
+	path := i.AddonStatesPath(kind, channel)
+	contents, err := json.MarshalIndent(states, "", "    ")
+	if err != nil {
+		return err
+	}
+	if dryrun.Skip(dryrun.WriteFile, path, fmt.Sprintf("%s states for %s", kind, channel.Name())) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, contents, 0644)
+}
+
+// SetAddonsEnabled switches the addons matching identifiers on or off for
+// channel and returns the names they are recorded under. Identifiers are
+// matched against installed addons first and then against existing entries,
+// so addons whose file is gone can still be switched off.
+func (i *BDInstall) SetAddonsEnabled(kind AddonKind, channel models.DiscordChannel, identifiers []string, enabled bool) ([]string, error) {
+	states, err := i.ReadAddonStates(kind, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := i.ListAddons(kind)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var names []string
+	for _, identifier := range identifiers {
+		name := ""
+		if entry := matchAddon(items, identifier); entry != nil {
+			name = states.entryKey(entry)
+		} else if key := states.key(identifier); states[key] != nil {
+			name = key
+		} else {
+			return nil, fmt.Errorf("%s %s not found", kind, identifier)
+		}
+		states.Set(name, enabled)
+		names = append(names, name)
+	}
+
+	if err := i.WriteAddonStates(kind, channel, states); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// AddonStatus is an installed addon together with whether it is enabled,
+// keyed by channel name.
+type AddonStatus struct {
+	AddonEntry
+	Enabled map[string]bool `json:"enabled"`
+}
+
+// StateChannels returns the channels that have a data/<channel> folder, and
+// so may have addons enabled. Stable is returned when none do yet.
+func (i *BDInstall) StateChannels() []models.DiscordChannel {
+	var channels []models.DiscordChannel
+	for _, channel := range models.Channels {
+		if info, err := os.Stat(filepath.Join(i.data, channel.String())); err == nil && info.IsDir() {
+			channels = append(channels, channel)
+		}
+	}
+	if len(channels) == 0 {
+		return []models.DiscordChannel{models.Stable}
+	}
+	return channels
+}
+
+// ListAddonStatus returns the installed addons of kind and whether each is
+// enabled for every channel in channels.
+func (i *BDInstall) ListAddonStatus(kind AddonKind, channels []models.DiscordChannel) ([]AddonStatus, error) {
+	items, err := i.ListAddons(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]AddonStatus, 0, len(items))
+	for _, item := range items {
+		statuses = append(statuses, AddonStatus{AddonEntry: item, Enabled: map[string]bool{}})
+	}
+	for _, channel := range channels {
+		states, err := i.ReadAddonStates(kind, channel)
+		if err != nil {
+			return nil, err
+		}
+		for n := range statuses {
+			statuses[n].Enabled[channel.String()] = states.Enabled(states.entryKey(&statuses[n].AddonEntry))
+		}
+	}
+	return statuses, nil
+}
@@ -0,0 +1,135 @@
+package betterdiscord
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/betterdiscord/cli/internal/models"
+)
+
+func TestReadAddonStates_Missing(t *testing.T) {
+	install := New(t.TempDir())
+
+	states, err := install.ReadAddonStates(AddonPlugin, models.Stable)
+	if err != nil {
+		t.Fatalf("ReadAddonStates() failed: %v", err)
+	}
+	if len(states) != 0 {
+		t.Errorf("ReadAddonStates() = %v, expected no entries", states)
+	}
+}
+
+func TestAddonStatesPath(t *testing.T) {
+	install := New("/bd")
+
+	tests := []struct {
+		kind     AddonKind
+		channel  models.DiscordChannel
+		expected string
+	}{
+		{AddonPlugin, models.Stable, filepath.Join("/bd", "data", "stable", "plugins.json")},
+		{AddonTheme, models.Canary, filepath.Join("/bd", "data", "canary", "themes.json")},
+	}
+
+	for _, tt := range tests {
+		if got := install.AddonStatesPath(tt.kind, tt.channel); got != tt.expected {
+			t.Errorf("AddonStatesPath(%s, %s) = %s, expected %s", tt.kind, tt.channel, got, tt.expected)
+		}
+	}
+}
+
+func TestSetAddonsEnabled(t *testing.T) {
+	install := newTestInstall(t)
+
+	os.WriteFile(filepath.Join(install.Plugins(), "Crashy.plugin.js"), []byte(validPluginNamed("Crashy")), 0644) //nolint:errcheck
+	path := install.AddonStatesPath(AddonPlugin, models.Stable)
+	os.MkdirAll(filepath.Dir(path), 0755)                                                                      //nolint:errcheck
+	os.WriteFile(path, []byte(`{"Crashy": true, "Removed": true, "Other": true, "extra": {"kept": 1}}`), 0644) //nolint:errcheck
+
+	names, err := install.SetAddonsEnabled(AddonPlugin, models.Stable, []string{"crashy.plugin.js", "removed"}, false)
+	if err != nil {
+		t.Fatalf("SetAddonsEnabled() failed: %v", err)
+	}
+	if len(names) != 2 || names[0] != "Crashy" || names[1] != "Removed" {
+		t.Errorf("SetAddonsEnabled() names = %v, expected [Crashy Removed]", names)
+	}
+
+	states, err := install.ReadAddonStates(AddonPlugin, models.Stable)
+	if err != nil {
+		t.Fatalf("ReadAddonStates() failed: %v", err)
+	}
+	if states.Enabled("Crashy") || states.Enabled("Removed") {
+		t.Error("disabled plugins are still enabled")
+	}
+	if !states.Enabled("Other") {
+		t.Error("other plugins should stay enabled")
+	}
+	var extra map[string]int
+	if err := json.Unmarshal(states["extra"], &extra); err != nil || extra["kept"] != 1 {
+		t.Errorf("unknown entries should be preserved, got %s", states["extra"])
+	}
+
+	if _, err := install.SetAddonsEnabled(AddonPlugin, models.Stable, []string{"Missing"}, false); err == nil {
+		t.Error("SetAddonsEnabled() should fail for an unknown plugin")
+	}
+}
+
+func validPluginNamed(name string) string {
+	return "/**\n * @name " + name + "\n * @version 1.0.0\n */\nmodule.exports = class {};\n"
+}
+
+func TestListAddonStatus(t *testing.T) {
+	install := newTestInstall(t)
+
+	os.WriteFile(filepath.Join(install.Plugins(), "Crashy.plugin.js"), []byte(validPluginNamed("Crashy")), 0644) //nolint:errcheck
+	os.WriteFile(filepath.Join(install.Plugins(), "Other.plugin.js"), []byte(validPluginNamed("Other")), 0644)   //nolint:errcheck
+	path := install.AddonStatesPath(AddonPlugin, models.Canary)
+	os.MkdirAll(filepath.Dir(path), 0755)               //nolint:errcheck
+	os.WriteFile(path, []byte(`{"other": true}`), 0644) //nolint:errcheck
+
+	if channels := install.StateChannels(); len(channels) != 1 || channels[0] != models.Canary {
+		t.Errorf("StateChannels() = %v, expected only canary", channels)
+	}
+
+	statuses, err := install.ListAddonStatus(AddonPlugin, []models.DiscordChannel{models.Stable, models.Canary})
+	if err != nil {
+		t.Fatalf("ListAddonStatus() failed: %v", err)
+	}
+	expected := map[string]map[string]bool{
+		"Crashy": {"stable": false, "canary": false},
+		"Other":  {"stable": false, "canary": true},
+	}
+	if len(statuses) != len(expected) {
+		t.Fatalf("ListAddonStatus() returned %d addons, expected %d", len(statuses), len(expected))
+	}
+	for _, status := range statuses {
+		if !reflect.DeepEqual(status.Enabled, expected[status.Meta.Name]) {
+			t.Errorf("ListAddonStatus() %s enabled = %v, expected %v", status.Meta.Name, status.Enabled, expected[status.Meta.Name])
+		}
+	}
+}
+
+func TestSetAddonsEnabled_Unnamed(t *testing.T) {
+	install := newTestInstall(t)
+
+	os.WriteFile(filepath.Join(install.Plugins(), "NoName.plugin.js"), []byte("module.exports = {};\n"), 0644) //nolint:errcheck
+
+	names, err := install.SetAddonsEnabled(AddonPlugin, models.Stable, []string{"noname.plugin.js"}, true)
+	if err != nil {
+		t.Fatalf("SetAddonsEnabled() failed: %v", err)
+	}
+	if len(names) != 1 || names[0] != "NoName" {
+		t.Errorf("SetAddonsEnabled() names = %v, expected [NoName]", names)
+	}
+
+	statuses, err := install.ListAddonStatus(AddonPlugin, []models.DiscordChannel{models.Stable})
+	if err != nil {
+		t.Fatalf("ListAddonStatus() failed: %v", err)
+	}
+	if len(statuses) != 1 || !statuses[0].Enabled["stable"] {
+		t.Errorf("ListAddonStatus() = %+v, expected the plugin without @name to be enabled", statuses)
+	}
+}
@@ -1,9 +1,11 @@
 package betterdiscord
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -13,12 +15,37 @@ import (
 	"github.com/betterdiscord/cli/internal/utils"
 )
 
+// DefaultStoreCacheTTL is how long store responses are used before they are revalidated.
+const DefaultStoreCacheTTL = time.Hour
+
+// StoreCache keeps store API responses on disk between runs.
+var StoreCache = &utils.Cache{Dir: storeCacheDir(), TTL: DefaultStoreCacheTTL, OnStale: warnStale}
+
+// warnStale tells the user that store data is served from an outdated cache.
+func warnStale(url string, fetched time.Time, err error) {
+	output.Printf("⚠️  The store could not be reached, using cached data from %s: %v\n", fetched.Local().Format(output.DateTimeFormat), err)
+}
+
+func storeCacheDir() string {
+	dir := utils.DefaultCacheDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "store")
+}
+
 // FetchAddonFromStore queries the BetterDiscord Store API by name or ID.
-// Returns addon metadata including download URL.
+// Returns addon metadata including download URL. In offline mode an addon
+// that was never looked up individually is found in the cached catalogue.
 func FetchAddonFromStore(identifier string) (*models.StoreAddon, error) {
-	apiURL := fmt.Sprintf("https://api.betterdiscord.app/v3/store/%s", url.PathEscape(identifier))
+	apiURL := storeURL(url.PathEscape(identifier))
 
-	addon, err := utils.DownloadJSON[models.StoreAddon](apiURL)
+	addon, err := utils.CachedJSON[models.StoreAddon](StoreCache, apiURL)
+	if errors.Is(err, utils.ErrNotCached) {
+		if cached := findCachedAddon(identifier); cached != nil {
+			return cached, nil
+		}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch addon '%s' from store: %w", identifier, err)
 	}
@@ -26,10 +53,34 @@ func FetchAddonFromStore(identifier string) (*models.StoreAddon, error) {
 	return &addon, nil
 }
 
+// findCachedAddon looks up identifier by ID, name or filename in the cached
+// catalogue of all addons.
+func findCachedAddon(identifier string) *models.StoreAddon {
+	addons, err := FetchAddonsOfType("addons")
+	if err != nil {
+		return nil
+	}
+
+	id, name, isID := ResolveAddonIdentifier(identifier)
+	for i := range addons {
+		if isID && addons[i].ID == id {
+			return &addons[i]
+		}
+		if !isID && (strings.EqualFold(addons[i].Name, name) || strings.EqualFold(addons[i].FileName, name)) {
+			return &addons[i]
+		}
+	}
+	return nil
+}
+
 // GetAddonDownloadURL resolves the final download URL for an addon by ID.
 // It follows redirects from the BetterDiscord download page.
 func GetAddonDownloadURL(id int) (s string, err error) {
-	downloadURL := fmt.Sprintf("https://betterdiscord.app/gh-redirect?id=%d", id)
+	if StoreCache.Offline || utils.Offline {
+		return "", fmt.Errorf("cannot download addons in offline mode")
+	}
+
+	downloadURL := addonRedirectURL(id)
 
 	req, err := http.NewRequest("GET", downloadURL, nil)
 	if err != nil {
@@ -142,9 +193,15 @@ func FetchAddonsOfType(kind string) ([]models.StoreAddon, error) {
 	default:
 		return nil, fmt.Errorf("invalid addon kind %q (expected plugin[s], theme[s], or addon[s])", kind)
 	}
-	apiURL := fmt.Sprintf("https://api.betterdiscord.app/v3/store/%s", endpoint)
+	apiURL := storeURL(endpoint)
 
-	addons, err := utils.DownloadJSON[[]models.StoreAddon](apiURL)
+	addons, err := utils.CachedJSON[[]models.StoreAddon](StoreCache, apiURL)
+	if errors.Is(err, utils.ErrNotCached) && endpoint != "addons" {
+		// Offline, but the full catalogue may still have been cached
+		if all, allErr := FetchAddonsOfType("addons"); allErr == nil {
+			return filterAddonsByType(all, strings.TrimSuffix(endpoint, "s")), nil
+		}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch %s from store: %w", endpoint, err)
 	}
@@ -152,6 +209,16 @@ func FetchAddonsOfType(kind string) ([]models.StoreAddon, error) {
 	return addons, nil
 }
 
+func filterAddonsByType(addons []models.StoreAddon, kind string) []models.StoreAddon {
+	var results []models.StoreAddon
+	for _, addon := range addons {
+		if strings.EqualFold(addon.Type, kind) {
+			results = append(results, addon)
+		}
+	}
+	return results
+}
+
 // SearchAddons performs a client-side search on addon slice.
 // Searches addon Name, Description, Author DisplayName, and FileName.
 func SearchAddons(addons []models.StoreAddon, query string) []models.StoreAddon {
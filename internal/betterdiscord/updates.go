@@ -0,0 +1,127 @@
+package betterdiscord
+
+import (
+	"strings"
+
+	"github.com/betterdiscord/cli/internal/models"
+	"github.com/betterdiscord/cli/internal/output"
+	"github.com/betterdiscord/cli/internal/semver"
+	"github.com/betterdiscord/cli/internal/utils"
+)
+
+// AddonUpdate pairs an installed addon with a newer release from the store.
+type AddonUpdate struct {
+	Entry AddonEntry
+	Store *models.StoreAddon
+}
+
+// Name returns the display name of the installed addon.
+func (u *AddonUpdate) Name() string {
+	if u.Entry.Meta.Name != "" {
+		return u.Entry.Meta.Name
+	}
+	return u.Entry.BaseName
+}
+
+// UpdateResult is the outcome of applying a single AddonUpdate.
+type UpdateResult struct {
+	AddonUpdate
+	Resolved *ResolvedAddon
+	Err      error
+}
+
+// CheckAddonUpdates compares the installed addons of a kind against the store.
+// The store catalogue is fetched once and matched locally. If the catalogue is
+// unavailable, addons are looked up individually with at most jobs concurrent
+// requests. Results keep the order of ListAddons. Addons that are newer than
+// the store release are only included when allowDowngrade is set.
+func CheckAddonUpdates(kind AddonKind, jobs int, allowDowngrade bool) ([]AddonUpdate, error) {
+	items, err := ListAddons(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	catalogue, catalogueErr := FetchAddonsOfType(string(kind))
+	if catalogueErr != nil {
+		output.Printf("⚠️  %s, checking addons individually\n", catalogueErr.Error())
+	}
+	index := newStoreIndex(catalogue)
+
+	matches := utils.ParallelMap(items, jobs, func(item AddonEntry) *models.StoreAddon {
+		if catalogueErr == nil {
+			return index.match(item)
+		}
+		addon, err := FetchAddonFromStore(addonIdentifier(item))
+		if err != nil {
+			return nil
+		}
+		return addon
+	})
+
+	var updates []AddonUpdate
+	for i, item := range items {
+		store := matches[i]
+		if store == nil {
+			// Not in the store, skip
+			continue
+		}
+		if NeedsUpdate(item.Meta.Version, store.Version, allowDowngrade) {
+			updates = append(updates, AddonUpdate{Entry: item, Store: store})
+		}
+	}
+	return updates, nil
+}
+
+// NeedsUpdate reports whether an addon at local version should be replaced by
+// the remote version.
+func NeedsUpdate(local, remote string, allowDowngrade bool) bool {
+	comparison := semver.Compare(local, remote)
+	return comparison < 0 || (comparison > 0 && allowDowngrade)
+}
+
+// UpdateAddons applies updates with at most jobs concurrent downloads.
+// Results are returned in the same order as updates.
+func UpdateAddons(kind AddonKind, updates []AddonUpdate, jobs int) []UpdateResult {
+	return utils.ParallelMap(updates, jobs, func(update AddonUpdate) UpdateResult {
+		resolved, err := UpdateInstalledAddon(kind, &update.Entry, update.Store)
+		return UpdateResult{AddonUpdate: update, Resolved: resolved, Err: err}
+	})
+}
+
+// addonIdentifier returns the name used to look up an installed addon in the store.
+func addonIdentifier(item AddonEntry) string {
+	if item.Meta.Name != "" {
+		return item.Meta.Name
+	}
+	return item.BaseName
+}
+
+// storeIndex matches installed addons against a store catalogue.
+type storeIndex struct {
+	byName     map[string]*models.StoreAddon
+	byFilename map[string]*models.StoreAddon
+}
+
+func newStoreIndex(addons []models.StoreAddon) *storeIndex {
+	index := &storeIndex{
+		byName:     map[string]*models.StoreAddon{},
+		byFilename: map[string]*models.StoreAddon{},
+	}
+	for i := range addons {
+		index.byName[strings.ToLower(addons[i].Name)] = &addons[i]
+		if addons[i].FileName != "" {
+			index.byFilename[strings.ToLower(addons[i].FileName)] = &addons[i]
+		}
+	}
+	return index
+}
+
+func (index *storeIndex) match(item AddonEntry) *models.StoreAddon {
+	if addon, ok := index.byName[strings.ToLower(addonIdentifier(item))]; ok {
+		return addon
+	}
+	if addon, ok := index.byFilename[strings.ToLower(item.FullFilename)]; ok {
+		return addon
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package betterdiscord
+
+import (
+	"testing"
+
+	"github.com/betterdiscord/cli/internal/models"
+)
+
+func TestStoreIndex_Match(t *testing.T) {
+	index := newStoreIndex([]models.StoreAddon{
+		{ID: 1, Name: "ExamplePlugin", FileName: "ExamplePlugin.plugin.js"},
+		{ID: 2, Name: "Other Plugin", FileName: "Renamed.plugin.js"},
+	})
+
+	tests := []struct {
+		name     string
+		entry    AddonEntry
+		expected int
+	}{
+		{
+			name:     "Match by meta name (case-insensitive)",
+			entry:    AddonEntry{BaseName: "whatever", Meta: Meta{Name: "exampleplugin"}},
+			expected: 1,
+		},
+		{
+			name:     "Match by filename",
+			entry:    AddonEntry{BaseName: "Renamed", FullFilename: "Renamed.plugin.js", Meta: Meta{Name: "Local Name"}},
+			expected: 2,
+		},
+		{
+			name:     "Fallback to base name",
+			entry:    AddonEntry{BaseName: "ExamplePlugin"},
+			expected: 1,
+		},
+		{
+			name:     "No match",
+			entry:    AddonEntry{BaseName: "Missing", FullFilename: "Missing.plugin.js"},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := index.match(tt.entry)
+			if tt.expected == 0 {
+				if result != nil {
+					t.Errorf("match() = %d, expected no match", result.ID)
+				}
+				return
+			}
+			if result == nil || result.ID != tt.expected {
+				t.Errorf("match() = %v, expected ID %d", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAddonUpdate_Name(t *testing.T) {
+	update := AddonUpdate{Entry: AddonEntry{BaseName: "file", Meta: Meta{Name: "Pretty"}}}
+	if update.Name() != "Pretty" {
+		t.Errorf("Name() = %s, expected Pretty", update.Name())
+	}
+
+	update = AddonUpdate{Entry: AddonEntry{BaseName: "file"}}
+	if update.Name() != "file" {
+		t.Errorf("Name() = %s, expected file", update.Name())
+	}
+}
+
+func TestNeedsUpdate(t *testing.T) {
+	tests := []struct {
+		local          string
+		remote         string
+		allowDowngrade bool
+		expected       bool
+	}{
+		{"1.0.0", "1.0.1", false, true},
+		{"1.0.9", "1.0.10", false, true},
+		{"1.0.0", "1.0.0", false, false},
+		{"v1.0.0", "1.0", false, false},
+		{"1.1.0", "1.0.0", false, false},
+		{"1.1.0", "1.0.0", true, true},
+		{"1.0.0-beta.1", "1.0.0", false, true},
+		{"1.0.0", "1.0.0-beta.1", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.local+"->"+tt.remote, func(t *testing.T) {
+			if result := NeedsUpdate(tt.local, tt.remote, tt.allowDowngrade); result != tt.expected {
+				t.Errorf("NeedsUpdate(%q, %q, %v) = %v, expected %v", tt.local, tt.remote, tt.allowDowngrade, result, tt.expected)
+			}
+		})
+	}
+}
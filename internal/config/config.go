@@ -0,0 +1,296 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/betterdiscord/cli/internal/dryrun"
+)
+
+// Config is the persistent configuration of the CLI. Fields left empty fall
+// back to the built-in defaults. Values are layered as flags, then BDCLI_*
+// environment variables, then the config file, then defaults.
+type Config struct {
+	Channel      string    `json:"channel,omitempty"`
+	DiscordPaths []string  `json:"discordPaths,omitempty"`
+	Root         string    `json:"root,omitempty"`
+	Endpoints    Endpoints `json:"endpoints,omitzero"`
+	Jobs         int       `json:"jobs,omitempty"`
+	Output       string    `json:"output,omitempty"`
+	Restart      *bool     `json:"restart,omitempty"`
+}
+
+// Endpoints overrides the base URLs of the services the CLI talks to.
+type Endpoints struct {
+	Store    string `json:"store,omitempty"`
+	Site     string `json:"site,omitempty"`
+	Releases string `json:"releases,omitempty"`
+}
+
+// Path returns the location of the config file. BDCLI_CONFIG overrides the
+// default of config.json in the user config directory.
+func Path() (string, error) {
+	if path := strings.TrimSpace(os.Getenv("BDCLI_CONFIG")); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to locate the user config directory: %w", err)
+	}
+	return filepath.Join(dir, "bdcli", "config.json"), nil
+}
+
+// Load reads the config file and applies environment overrides on top of it.
+// A missing config file is not an error.
+func Load() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.ApplyEnv(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// LoadFile reads the config file at path without applying the environment.
+func LoadFile(path string) (*Config, error) {
+	cfg := &Config{}
+
+	contents, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(contents, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Save writes the config to path, creating its folder if needed.
+func (c *Config) Save(path string) error {
+	if dryrun.Skip(dryrun.WriteFile, path, "config file") {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	contents, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(contents, '\n'), 0644)
+}
+
+// ApplyEnv overrides config values with their BDCLI_* environment variables.
+func (c *Config) ApplyEnv() error {
+	for _, key := range Keys {
+		if key.Env == "" {
+			continue
+		}
+		value := strings.TrimSpace(os.Getenv(key.Env))
+		if value == "" {
+			continue
+		}
+		if key.Name == "discord.paths" {
+			// Paths are listed like PATH rather than comma separated
+			c.DiscordPaths = filepath.SplitList(value)
+			continue
+		}
+		if err := key.set(c, value); err != nil {
+			return fmt.Errorf("invalid %s: %w", key.Env, err)
+		}
+	}
+	return nil
+}
+
+// RestartEnabled reports whether Discord should be restarted after changes.
+func (c *Config) RestartEnabled() bool {
+	return c.Restart == nil || *c.Restart
+}
+
+// Key describes a single setting that can be read and written by name.
+type Key struct {
+	Name        string
+	Env         string
+	Description string
+	get         func(*Config) string
+	set         func(*Config, string) error
+	unset       func(*Config)
+}
+
+// Keys lists every supported setting in display order.
+var Keys = []Key{
+	{
+		Name:        "channel",
+		Env:         "BDCLI_CHANNEL",
+		Description: "Default Discord release channel (stable|ptb|canary)",
+		get:         func(c *Config) string { return c.Channel },
+		set: func(c *Config, value string) error {
+			value = strings.ToLower(value)
+			if value != "stable" && value != "ptb" && value != "canary" {
+				return fmt.Errorf("invalid channel %q (expected stable, ptb, or canary)", value)
+			}
+			c.Channel = value
+			return nil
+		},
+		unset: func(c *Config) { c.Channel = "" },
+	},
+	{
+		Name:        "discord.paths",
+		Env:         "BDCLI_DISCORD_PATHS",
+		Description: "Additional Discord installation paths, comma separated",
+		get:         func(c *Config) string { return strings.Join(c.DiscordPaths, ",") },
+		set: func(c *Config, value string) error {
+			var paths []string
+			for path := range strings.SplitSeq(value, ",") {
+				if path = strings.TrimSpace(path); path != "" {
+					paths = append(paths, path)
+				}
+			}
+			c.DiscordPaths = paths
+			return nil
+		},
+		unset: func(c *Config) { c.DiscordPaths = nil },
+	},
+	{
+		Name:        "root",
+		Env:         "BDCLI_ROOT",
+		Description: "BetterDiscord root directory (contains data, plugins, and themes)",
+		get:         func(c *Config) string { return c.Root },
+		set: func(c *Config, value string) error {
+			root, err := filepath.Abs(value)
+			if err != nil {
+				return fmt.Errorf("invalid root %q: %w", value, err)
+			}
+			c.Root = root
+			return nil
+		},
+		unset: func(c *Config) { c.Root = "" },
+	},
+	endpointKey("endpoints.store", "BDCLI_STORE_URL", "Store API base URL", func(c *Config) *string { return &c.Endpoints.Store }),
+	endpointKey("endpoints.site", "BDCLI_SITE_URL", "BetterDiscord website base URL", func(c *Config) *string { return &c.Endpoints.Site }),
+	endpointKey("endpoints.releases", "BDCLI_RELEASES_URL", "GitHub releases API base URL", func(c *Config) *string { return &c.Endpoints.Releases }),
+	{
+		Name:        "jobs",
+		Env:         "BDCLI_JOBS",
+		Description: "Concurrent checks and downloads for update --all",
+		get: func(c *Config) string {
+			if c.Jobs == 0 {
+				return ""
+			}
+			return strconv.Itoa(c.Jobs)
+		},
+		set: func(c *Config, value string) error {
+			jobs, err := strconv.Atoi(value)
+			if err != nil || jobs < 1 {
+				return fmt.Errorf("invalid jobs %q (expected a positive number)", value)
+			}
+			c.Jobs = jobs
+			return nil
+		},
+		unset: func(c *Config) { c.Jobs = 0 },
+	},
+	{
+		Name:        "output",
+		Env:         "BDCLI_OUTPUT",
+		Description: "Default output format (table|json|yaml)",
+		get:         func(c *Config) string { return c.Output },
+		set: func(c *Config, value string) error {
+			value = strings.ToLower(value)
+			switch value {
+			case "table", "json", "yaml", "yml":
+				c.Output = value
+				return nil
+			}
+			return fmt.Errorf("invalid output format %q (expected table, json, or yaml)", value)
+		},
+		unset: func(c *Config) { c.Output = "" },
+	},
+	{
+		Name:        "restart",
+		Env:         "BDCLI_RESTART",
+		Description: "Restart Discord after install, uninstall, repair, and watch (true|false)",
+		get: func(c *Config) string {
+			if c.Restart == nil {
+				return ""
+			}
+			return strconv.FormatBool(*c.Restart)
+		},
+		set: func(c *Config, value string) error {
+			restart, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid restart %q (expected true or false)", value)
+			}
+			c.Restart = &restart
+			return nil
+		},
+		unset: func(c *Config) { c.Restart = nil },
+	},
+}
+
+func endpointKey(name, env, description string, field func(*Config) *string) Key {
+	return Key{
+		Name:        name,
+		Env:         env,
+		Description: description,
+		get:         func(c *Config) string { return *field(c) },
+		set: func(c *Config, value string) error {
+			if !strings.HasPrefix(value, "http://") && !strings.HasPrefix(value, "https://") {
+				return fmt.Errorf("invalid URL %q (expected http or https)", value)
+			}
+			*field(c) = strings.TrimRight(value, "/")
+			return nil
+		},
+		unset: func(c *Config) { *field(c) = "" },
+	}
+}
+
+// FindKey returns the setting called name.
+func FindKey(name string) (*Key, error) {
+	for i := range Keys {
+		if Keys[i].Name == strings.ToLower(name) {
+			return &Keys[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unknown config key %q", name)
+}
+
+// Get returns the value of the setting called name, or an empty string if unset.
+func (c *Config) Get(name string) (string, error) {
+	key, err := FindKey(name)
+	if err != nil {
+		return "", err
+	}
+	return key.get(c), nil
+}
+
+// Set validates and stores value for the setting called name.
+func (c *Config) Set(name, value string) error {
+	key, err := FindKey(name)
+	if err != nil {
+		return err
+	}
+	return key.set(c, strings.TrimSpace(value))
+}
+
+// Unset clears the setting called name so its default applies again.
+func (c *Config) Unset(name string) error {
+	key, err := FindKey(name)
+	if err != nil {
+		return err
+	}
+	key.unset(c)
+	return nil
+}
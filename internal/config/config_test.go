@@ -0,0 +1,153 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFile_Missing(t *testing.T) {
+	cfg, err := LoadFile(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatalf("LoadFile() failed: %v", err)
+	}
+	if cfg.Endpoints != (Endpoints{}) {
+		t.Errorf("expected empty endpoints, got %+v", cfg.Endpoints)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(path, []byte(`{"endpoints":{"store":"https://mirror.example/store"}}`), 0644) //nolint:errcheck
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() failed: %v", err)
+	}
+	if cfg.Endpoints.Store != "https://mirror.example/store" {
+		t.Errorf("Store = %s", cfg.Endpoints.Store)
+	}
+}
+
+func TestLoadFile_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(path, []byte(`{not json`), 0644) //nolint:errcheck
+
+	if _, err := LoadFile(path); err == nil {
+		t.Error("LoadFile() should fail on invalid JSON")
+	}
+}
+
+func TestLoad_EnvOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(path, []byte(`{"endpoints":{"store":"https://file.example","site":"https://site.example"}}`), 0644) //nolint:errcheck
+
+	t.Setenv("BDCLI_CONFIG", path)
+	t.Setenv("BDCLI_STORE_URL", "https://env.example")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Endpoints.Store != "https://env.example" {
+		t.Errorf("Store = %s, expected the environment to win", cfg.Endpoints.Store)
+	}
+	if cfg.Endpoints.Site != "https://site.example" {
+		t.Errorf("Site = %s, expected the config file value", cfg.Endpoints.Site)
+	}
+}
+
+func TestConfig_SetGetUnset(t *testing.T) {
+	tests := []struct {
+		key      string
+		value    string
+		expected string
+		wantErr  bool
+	}{
+		{key: "channel", value: "Canary", expected: "canary"},
+		{key: "channel", value: "beta", wantErr: true},
+		{key: "discord.paths", value: "/opt/discord, /opt/discord-ptb", expected: "/opt/discord,/opt/discord-ptb"},
+		{key: "root", value: "/portable/BetterDiscord", expected: filepath.Clean("/portable/BetterDiscord")},
+		{key: "endpoints.store", value: "https://mirror.example/store/", expected: "https://mirror.example/store"},
+		{key: "endpoints.site", value: "mirror.example", wantErr: true},
+		{key: "jobs", value: "8", expected: "8"},
+		{key: "jobs", value: "0", wantErr: true},
+		{key: "output", value: "JSON", expected: "json"},
+		{key: "output", value: "xml", wantErr: true},
+		{key: "restart", value: "false", expected: "false"},
+		{key: "restart", value: "sometimes", wantErr: true},
+		{key: "unknown", value: "1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			cfg := &Config{}
+			err := cfg.Set(tt.key, tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Set(%q, %q) should fail", tt.key, tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Set(%q, %q) failed: %v", tt.key, tt.value, err)
+			}
+
+			if value, _ := cfg.Get(tt.key); value != tt.expected {
+				t.Errorf("Get(%q) = %q, expected %q", tt.key, value, tt.expected)
+			}
+
+			if err := cfg.Unset(tt.key); err != nil {
+				t.Fatalf("Unset(%q) failed: %v", tt.key, err)
+			}
+			if value, _ := cfg.Get(tt.key); value != "" {
+				t.Errorf("Get(%q) after Unset() = %q, expected empty", tt.key, value)
+			}
+		})
+	}
+}
+
+func TestConfig_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "config.json")
+
+	cfg := &Config{}
+	cfg.Set("channel", "ptb")   //nolint:errcheck
+	cfg.Set("restart", "false") //nolint:errcheck
+	cfg.Set("jobs", "2")        //nolint:errcheck
+	if err := cfg.Save(path); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	loaded, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() failed: %v", err)
+	}
+	if loaded.Channel != "ptb" || loaded.Jobs != 2 || loaded.RestartEnabled() {
+		t.Errorf("unexpected config after round trip: %+v", loaded)
+	}
+}
+
+func TestConfig_ApplyEnv(t *testing.T) {
+	t.Setenv("BDCLI_CHANNEL", "canary")
+	t.Setenv("BDCLI_DISCORD_PATHS", "/a"+string(filepath.ListSeparator)+"/b")
+	t.Setenv("BDCLI_RESTART", "0")
+
+	cfg := &Config{Channel: "ptb"}
+	if err := cfg.ApplyEnv(); err != nil {
+		t.Fatalf("ApplyEnv() failed: %v", err)
+	}
+	if cfg.Channel != "canary" {
+		t.Errorf("Channel = %s, expected the environment to win", cfg.Channel)
+	}
+	if len(cfg.DiscordPaths) != 2 || cfg.DiscordPaths[1] != "/b" {
+		t.Errorf("DiscordPaths = %v", cfg.DiscordPaths)
+	}
+	if cfg.RestartEnabled() {
+		t.Error("RestartEnabled() should be false")
+	}
+
+	t.Setenv("BDCLI_JOBS", "many")
+	if err := cfg.ApplyEnv(); err == nil {
+		t.Error("ApplyEnv() should reject an invalid BDCLI_JOBS")
+	}
+}
@@ -2,29 +2,228 @@ package discord
 
 import (
 	_ "embed"
+	"encoding/json"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/betterdiscord/cli/internal/betterdiscord"
+	"github.com/betterdiscord/cli/internal/dryrun"
 	"github.com/betterdiscord/cli/internal/output"
+	"github.com/betterdiscord/cli/internal/semver"
+	"github.com/betterdiscord/cli/internal/wsl"
 )
 
-//go:embed assets/injection.js
-var injectionScript string
+//go:embed assets/injection.js.tmpl
+var injectionTemplate string
+
+var injectionScript = template.Must(template.New("injection.js").Funcs(template.FuncMap{
+	"json": func(value string) (string, error) {
+		quoted, err := json.Marshal(value)
+		return string(quoted), err
+	},
+}).Parse(injectionTemplate))
+
+// defaultIndex is Discord's stock index.js, used when no original was saved.
+const defaultIndex = `module.exports = require("./core.asar");`
+
+// InjectionFormat is the version of the marker header. Injections with an
+// older format are reported as stale.
+const InjectionFormat = 1
+
+// markerPrefix starts the marker header line in an injected index.js.
+const markerPrefix = "// bdcli:injection "
+
+// CLIVersion is the version of the CLI recorded in new injections.
+var CLIVersion = "dev"
+
+// legacyInjectionRegex matches the require of the asar in scripts written
+// before the marker header existed.
+var legacyInjectionRegex = regexp.MustCompile(`(?i)require\(.*betterdiscord\.asar`)
+
+// InjectionMarker is the header written at the top of an injected index.js.
+type InjectionMarker struct {
+	Format    int       `json:"format"`
+	CLI       string    `json:"cli"`
+	Root      string    `json:"root"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// String renders the marker as the header comment line.
+func (m InjectionMarker) String() string {
+	contents, _ := json.Marshal(m)
+	return markerPrefix + string(contents)
+}
+
+// InjectionState describes the injection found in an index.js.
+type InjectionState string
+
+const (
+	InjectionNone    InjectionState = "none"
+	InjectionCurrent InjectionState = "current"
+	InjectionStale   InjectionState = "stale"
+)
+
+// InjectionStatus is the result of inspecting an install's index.js.
+type InjectionStatus struct {
+	State  InjectionState   `json:"state"`
+	Marker *InjectionMarker `json:"marker,omitempty"`
+	Reason string           `json:"reason,omitempty"`
+}
+
+// injectionData is the data available to the injection script template.
+type injectionData struct {
+	// Marker is the header line identifying the injection.
+	Marker string
+	// Root is the BetterDiscord folder as seen by Discord. When empty the
+	// script locates the default folder itself at runtime.
+	Root string
+}
+
+// renderInjection renders the injection script for data.
+func renderInjection(data injectionData) (string, error) {
+	var script strings.Builder
+	if err := injectionScript.Execute(&script, data); err != nil {
+		return "", err
+	}
+	return script.String(), nil
+}
+
+// injectionRoot returns the BetterDiscord root to hard-code into the
+// injection script, or an empty string when the default location is used.
+func injectionRoot(bd *betterdiscord.BDInstall) (string, error) {
+	if !betterdiscord.HasRootOverride() {
+		return "", nil
+	}
+
+	// Windows Discord needs the Windows form of the WSL path
+	if wsl.IsWSL() {
+		return wsl.ToWindowsPath(bd.Root())
+	}
+	return bd.Root(), nil
+}
+
+// parseMarker returns the marker header in contents, if any.
+func parseMarker(contents string) *InjectionMarker {
+	for line := range strings.SplitSeq(contents, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, markerPrefix) {
+			continue
+		}
+		var marker InjectionMarker
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, markerPrefix)), &marker); err != nil {
+			return nil
+		}
+		return &marker
+	}
+	return nil
+}
+
+// isLegacyInjection reports whether contents requires the BetterDiscord asar
+// outside of a comment.
+func isLegacyInjection(contents string) bool {
+	for line := range strings.SplitSeq(contents, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "//") {
+			continue
+		}
+		if legacyInjectionRegex.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// inspectInjection classifies contents against the injection this CLI would write for root.
+func inspectInjection(contents, root string) InjectionStatus {
+	marker := parseMarker(contents)
+	if marker == nil {
+		if isLegacyInjection(contents) {
+			return InjectionStatus{State: InjectionStale, Reason: "injected by an older bdcli without a version marker"}
+		}
+		return InjectionStatus{State: InjectionNone}
+	}
+
+	status := InjectionStatus{State: InjectionStale, Marker: marker}
+	switch {
+	case marker.Format < InjectionFormat:
+		status.Reason = fmt.Sprintf("injection format %d is older than %d", marker.Format, InjectionFormat)
+	case marker.CLI != "dev" && CLIVersion != "dev" && semver.Compare(marker.CLI, CLIVersion) < 0:
+		status.Reason = fmt.Sprintf("injected by bdcli %s", marker.CLI)
+	case marker.Root != root:
+		status.Reason = fmt.Sprintf("points to %s", marker.Root)
+	default:
+		status.State = InjectionCurrent
+	}
+	return status
+}
+
+// InjectionStatus reports whether this install is injected and whether the
+// injection is current for this CLI and BetterDiscord root.
+func (discord *DiscordInstall) InjectionStatus() InjectionStatus {
+	contents, err := os.ReadFile(filepath.Join(discord.CorePath, "index.js"))
+	if err != nil {
+		return InjectionStatus{State: InjectionNone}
+	}
+	return inspectInjection(string(contents), discord.GetBetterDiscordInstall().Root())
+}
 
 func (discord *DiscordInstall) inject(bd *betterdiscord.BDInstall) error {
 	if discord.IsFlatpak {
 		cmd := exec.Command("flatpak", "--user", "override", "com.discordapp."+discord.Channel.Exe(), "--filesystem="+bd.Root())
-		if err := cmd.Run(); err != nil {
-			output.Printf("❌ Could not give flatpak access to %s\n", bd.Root())
-			output.Printf("   %s\n", err.Error())
-			return err
+		if !dryrun.Skip(dryrun.Run, strings.Join(cmd.Args, " "), "give flatpak access to the BetterDiscord folder") {
+			if err := cmd.Run(); err != nil {
+				output.Printf("❌ Could not give flatpak access to %s\n", bd.Root())
+				output.Printf("   %s\n", err.Error())
+				return err
+			}
 		}
 	}
 
-	if err := os.WriteFile(filepath.Join(discord.CorePath, "index.js"), []byte(injectionScript), 0755); err != nil {
+	indexFile := filepath.Join(discord.CorePath, "index.js")
+
+	// Keep Discord's own index.js so uninject can put it back exactly
+	if contents, err := os.ReadFile(indexFile); err == nil {
+		status := inspectInjection(string(contents), bd.Root())
+		switch status.State {
+		case InjectionNone:
+			if err := discord.saveOriginal(bd, contents); err != nil {
+				output.Printf("❌ Unable to save the original index.js from %s\n", discord.CorePath)
+				output.Printf("   %s\n", err.Error())
+				return err
+			}
+		case InjectionStale:
+			output.Printf("🔁 Upgrading stale injection (%s)\n", status.Reason)
+		}
+	}
+
+	root, err := injectionRoot(bd)
+	if err != nil {
+		output.Printf("❌ Unable to resolve the BetterDiscord folder %s for Discord\n", bd.Root())
+		output.Printf("   %s\n", err.Error())
+		return err
+	}
+	marker := InjectionMarker{
+		Format:    InjectionFormat,
+		CLI:       CLIVersion,
+		Root:      bd.Root(),
+		Timestamp: time.Now().UTC().Truncate(time.Second),
+	}
+	script, err := renderInjection(injectionData{Marker: marker.String(), Root: root})
+	if err != nil {
+		return err
+	}
+
+	if dryrun.Skip(dryrun.WriteFile, indexFile, "BetterDiscord injection") {
+		return nil
+	}
+
+	if err := os.WriteFile(indexFile, []byte(script), 0755); err != nil {
 		output.Printf("❌ Unable to write index.js in %s\n", discord.CorePath)
 		output.Printf("   %s\n", err.Error())
 		return err
@@ -37,33 +236,39 @@ func (discord *DiscordInstall) inject(bd *betterdiscord.BDInstall) error {
 func (discord *DiscordInstall) uninject() error {
 	indexFile := filepath.Join(discord.CorePath, "index.js")
 
-	contents, err := os.ReadFile(indexFile)
-
 	// First try to check the file, but if there's an issue we try to blindly overwrite below
-	if err == nil {
-		if !strings.Contains(strings.ToLower(string(contents)), "betterdiscord") {
-			output.Printf("✅ No injection found for %s\n", discord.Channel.Name())
-			return nil
-		}
+	if _, err := os.Stat(indexFile); err == nil && discord.InjectionStatus().State == InjectionNone {
+		output.Printf("✅ No injection found for %s\n", discord.Channel.Name())
+		return nil
 	}
 
-	if err := os.WriteFile(indexFile, []byte(`module.exports = require("./core.asar");`), 0o644); err != nil {
+	bd := discord.GetBetterDiscordInstall()
+	original, err := discord.readOriginal(bd)
+	restored := "original index.js"
+	if err != nil {
+		output.Printf("⚠️  Unable to restore the saved index.js for %s, writing Discord's default\n", discord.Channel.Name())
+		output.Printf("   %s\n", err.Error())
+		original = []byte(defaultIndex)
+		restored = "Discord's default index.js"
+	}
+
+	if dryrun.Skip(dryrun.WriteFile, indexFile, restored) {
+		dryrun.Skip(dryrun.Remove, discord.originalDir(bd), "saved original index.js")
+		return nil
+	}
+
+	if err := os.WriteFile(indexFile, original, 0o644); err != nil {
 		output.Printf("❌ Unable to write file %s\n", indexFile)
 		output.Printf("   %s\n", err.Error())
 		return err
 	}
+	os.RemoveAll(discord.originalDir(bd)) //nolint:errcheck
 	output.Printf("✅ Removed from %s\n", discord.Channel.Name())
 
 	return nil
 }
 
-// TODO: consider putting this in the betterdiscord package
+// IsInjected reports whether index.js loads BetterDiscord, current or not.
 func (discord *DiscordInstall) IsInjected() bool {
-	indexFile := filepath.Join(discord.CorePath, "index.js")
-	contents, err := os.ReadFile(indexFile)
-	if err != nil {
-		return false
-	}
-	lower := strings.ToLower(string(contents))
-	return strings.Contains(lower, "betterdiscord")
+	return discord.InjectionStatus().State != InjectionNone
 }
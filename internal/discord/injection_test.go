@@ -0,0 +1,263 @@
+package discord
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/betterdiscord/cli/internal/betterdiscord"
+	"github.com/betterdiscord/cli/internal/dryrun"
+	"github.com/betterdiscord/cli/internal/models"
+)
+
+func TestRenderInjection_Default(t *testing.T) {
+	script, err := renderInjection(injectionData{})
+	if err != nil {
+		t.Fatalf("renderInjection() failed: %v", err)
+	}
+
+	if !strings.Contains(script, `path.join(userConfig, "BetterDiscord", "data", "betterdiscord.asar")`) {
+		t.Error("default script should locate BetterDiscord from the user config folder")
+	}
+	if !strings.HasSuffix(script, `module.exports = require("./core.asar");`) {
+		t.Error("script should keep Discord's default export")
+	}
+}
+
+func TestRenderInjection_CustomRoot(t *testing.T) {
+	script, err := renderInjection(injectionData{Root: `C:\Portable "BD"\BetterDiscord`})
+	if err != nil {
+		t.Fatalf("renderInjection() failed: %v", err)
+	}
+
+	expected := `require(path.join("C:\\Portable \"BD\"\\BetterDiscord", "data", "betterdiscord.asar"));`
+	if !strings.Contains(script, expected) {
+		t.Errorf("script should require the asar from the custom root, got:\n%s", script)
+	}
+	if strings.Contains(script, "userConfig") {
+		t.Error("custom root script should not fall back to the user config folder")
+	}
+	if !strings.HasSuffix(script, `module.exports = require("./core.asar");`) {
+		t.Error("script should keep Discord's default export")
+	}
+}
+
+func TestParseMarker(t *testing.T) {
+	marker := InjectionMarker{Format: InjectionFormat, CLI: "1.2.3", Root: "/bd", Timestamp: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	script, err := renderInjection(injectionData{Marker: marker.String()})
+	if err != nil {
+		t.Fatalf("renderInjection() failed: %v", err)
+	}
+
+	parsed := parseMarker(script)
+	if parsed == nil {
+		t.Fatal("parseMarker() did not find the marker")
+	}
+	if *parsed != marker {
+		t.Errorf("parseMarker() = %+v, expected %+v", *parsed, marker)
+	}
+}
+
+func TestInspectInjection(t *testing.T) {
+	previous := CLIVersion
+	CLIVersion = "1.2.0"
+	t.Cleanup(func() { CLIVersion = previous })
+
+	marker := func(cli, root string) string {
+		return InjectionMarker{Format: InjectionFormat, CLI: cli, Root: root}.String() + "\n" + defaultIndex
+	}
+
+	tests := []struct {
+		name     string
+		contents string
+		expected InjectionState
+	}{
+		{"stock", defaultIndex, InjectionNone},
+		{"comment only", "// not betterdiscord\n" + defaultIndex, InjectionNone},
+		{"legacy", `require(path.join(userConfig, "BetterDiscord", "data", "betterdiscord.asar"));`, InjectionStale},
+		{"current", marker("1.2.0", "/bd"), InjectionCurrent},
+		{"newer cli", marker("1.3.0", "/bd"), InjectionCurrent},
+		{"older cli", marker("1.1.9", "/bd"), InjectionStale},
+		{"other root", marker("1.2.0", "/elsewhere"), InjectionStale},
+		{"old format", `// bdcli:injection {"format":0,"cli":"1.2.0","root":"/bd"}`, InjectionStale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := inspectInjection(tt.contents, "/bd")
+			if status.State != tt.expected {
+				t.Errorf("inspectInjection() = %s (%s), expected %s", status.State, status.Reason, tt.expected)
+			}
+			if status.State == InjectionStale && status.Reason == "" {
+				t.Error("stale injections should carry a reason")
+			}
+		})
+	}
+}
+
+func TestInjectUninject_RestoresOriginal(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "BetterDiscord")
+	betterdiscord.SetRoot(root)
+	t.Cleanup(func() { betterdiscord.SetRoot("") })
+
+	corePath := filepath.Join(t.TempDir(), "discord_desktop_core")
+	os.MkdirAll(corePath, 0755) //nolint:errcheck
+	original := "// patched by another tool\n" + defaultIndex
+	os.WriteFile(filepath.Join(corePath, "index.js"), []byte(original), 0644) //nolint:errcheck
+
+	install := &DiscordInstall{CorePath: corePath, Channel: models.Stable}
+	bd := install.GetBetterDiscordInstall()
+
+	if err := install.inject(bd); err != nil {
+		t.Fatalf("inject() failed: %v", err)
+	}
+	if state := install.InjectionStatus().State; state != InjectionCurrent {
+		t.Fatalf("InjectionStatus() = %s after inject, expected current", state)
+	}
+
+	// Injecting again must not replace the saved original with the injection
+	if err := install.inject(bd); err != nil {
+		t.Fatalf("second inject() failed: %v", err)
+	}
+
+	if err := install.uninject(); err != nil {
+		t.Fatalf("uninject() failed: %v", err)
+	}
+	contents, _ := os.ReadFile(filepath.Join(corePath, "index.js"))
+	if string(contents) != original {
+		t.Errorf("uninject() wrote %q, expected the original index.js", string(contents))
+	}
+}
+
+func TestRepairBD_ReinjectKeepsOriginal(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "BetterDiscord")
+	betterdiscord.SetRoot(root)
+	t.Cleanup(func() { betterdiscord.SetRoot("") })
+	AutoRestart = false
+	t.Cleanup(func() { AutoRestart = true })
+
+	corePath := filepath.Join(t.TempDir(), "discord_desktop_core")
+	os.MkdirAll(corePath, 0755) //nolint:errcheck
+	original := "// patched by another tool\n" + defaultIndex
+	os.WriteFile(filepath.Join(corePath, "index.js"), []byte(original), 0644) //nolint:errcheck
+
+	install := &DiscordInstall{CorePath: corePath, Channel: models.Stable}
+	bd := install.GetBetterDiscordInstall()
+	if err := install.inject(bd); err != nil {
+		t.Fatalf("inject() failed: %v", err)
+	}
+
+	if err := install.RepairBD(RepairOptions{Reinject: true}); err != nil {
+		t.Fatalf("RepairBD() failed: %v", err)
+	}
+	if state := install.InjectionStatus().State; state != InjectionCurrent {
+		t.Fatalf("InjectionStatus() = %s after repair, expected current", state)
+	}
+	snapshot, err := install.readOriginal(bd)
+	if err != nil {
+		t.Fatalf("readOriginal() failed after repair: %v", err)
+	}
+	if string(snapshot) != original {
+		t.Errorf("snapshot after repair = %q, expected the original index.js", string(snapshot))
+	}
+}
+
+func TestUninject_TamperedSnapshot(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "BetterDiscord")
+	betterdiscord.SetRoot(root)
+	t.Cleanup(func() { betterdiscord.SetRoot("") })
+
+	corePath := filepath.Join(t.TempDir(), "discord_desktop_core")
+	os.MkdirAll(corePath, 0755)                                                                  //nolint:errcheck
+	os.WriteFile(filepath.Join(corePath, "index.js"), []byte("// vencord\n"+defaultIndex), 0644) //nolint:errcheck
+
+	install := &DiscordInstall{CorePath: corePath, Channel: models.Stable}
+	bd := install.GetBetterDiscordInstall()
+	if err := install.inject(bd); err != nil {
+		t.Fatalf("inject() failed: %v", err)
+	}
+	if original := install.OriginalIndex(); original == nil || original.CorePath != corePath {
+		t.Fatalf("OriginalIndex() = %+v, expected a snapshot for %s", original, corePath)
+	}
+
+	os.WriteFile(filepath.Join(install.originalDir(bd), "index.js"), []byte("tampered"), 0644) //nolint:errcheck
+
+	if err := install.uninject(); err != nil {
+		t.Fatalf("uninject() failed: %v", err)
+	}
+	contents, _ := os.ReadFile(filepath.Join(corePath, "index.js"))
+	if string(contents) != defaultIndex {
+		t.Errorf("uninject() wrote %q, expected Discord's default for a tampered snapshot", string(contents))
+	}
+}
+
+func TestIndexUnrecognized(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "BetterDiscord")
+	betterdiscord.SetRoot(root)
+	t.Cleanup(func() { betterdiscord.SetRoot("") })
+
+	corePath := filepath.Join(t.TempDir(), "discord_desktop_core")
+	indexFile := filepath.Join(corePath, "index.js")
+	os.MkdirAll(corePath, 0755)                         //nolint:errcheck
+	os.WriteFile(indexFile, []byte(defaultIndex), 0644) //nolint:errcheck
+
+	install := &DiscordInstall{CorePath: corePath, Channel: models.Stable}
+	if install.IndexUnrecognized() {
+		t.Error("IndexUnrecognized() should be false without a snapshot")
+	}
+
+	if err := install.inject(install.GetBetterDiscordInstall()); err != nil {
+		t.Fatalf("inject() failed: %v", err)
+	}
+	if install.IndexUnrecognized() {
+		t.Error("IndexUnrecognized() should be false while injected")
+	}
+
+	os.WriteFile(indexFile, []byte("// replaced by another mod"), 0644) //nolint:errcheck
+	if !install.IndexUnrecognized() {
+		t.Error("IndexUnrecognized() should be true when index.js matches neither")
+	}
+
+	os.WriteFile(indexFile, []byte(defaultIndex), 0644) //nolint:errcheck
+	if install.IndexUnrecognized() {
+		t.Error("IndexUnrecognized() should be false when index.js matches the snapshot")
+	}
+}
+
+func TestInjectUninject_DryRun(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "BetterDiscord")
+	betterdiscord.SetRoot(root)
+	t.Cleanup(func() { betterdiscord.SetRoot("") })
+
+	corePath := filepath.Join(t.TempDir(), "discord_desktop_core")
+	indexFile := filepath.Join(corePath, "index.js")
+	os.MkdirAll(corePath, 0755)                         //nolint:errcheck
+	os.WriteFile(indexFile, []byte(defaultIndex), 0644) //nolint:errcheck
+
+	dryrun.Enable(true)
+	t.Cleanup(func() { dryrun.Enable(false) })
+
+	install := &DiscordInstall{CorePath: corePath, Channel: models.Stable}
+	if err := install.inject(install.GetBetterDiscordInstall()); err != nil {
+		t.Fatalf("inject() failed: %v", err)
+	}
+
+	contents, _ := os.ReadFile(indexFile)
+	if string(contents) != defaultIndex {
+		t.Errorf("dry-run inject() changed index.js to %q", string(contents))
+	}
+	if _, err := os.Stat(root); !os.IsNotExist(err) {
+		t.Error("dry-run inject() should not create the BetterDiscord folder")
+	}
+
+	var targets []string
+	for _, action := range dryrun.Actions() {
+		targets = append(targets, action.Target)
+	}
+	if !slices.Contains(targets, indexFile) {
+		t.Errorf("dry-run inject() did not record writing index.js, got %v", targets)
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"github.com/betterdiscord/cli/internal/output"
 )
 
+// AutoRestart controls whether Discord is restarted after installing or uninstalling.
+var AutoRestart = true
+
 type DiscordInstall struct {
 	CorePath  string                `json:"corePath"`
 	Channel   models.DiscordChannel `json:"channel"`
@@ -16,6 +19,19 @@ type DiscordInstall struct {
 	IsSnap    bool                  `json:"isSnap"`
 }
 
+// InstallTypes lists the packaging types Type can report.
+var InstallTypes = []string{"native", "flatpak", "snap"}
+
+// Type returns the packaging type of this Discord installation (native, flatpak, or snap)
+func (discord *DiscordInstall) Type() string {
+	if discord.IsFlatpak {
+		return "flatpak"
+	} else if discord.IsSnap {
+		return "snap"
+	}
+	return "native"
+}
+
 // InstallBD installs BetterDiscord into this Discord installation
 func (discord *DiscordInstall) InstallBD() error {
 	bd := discord.GetBetterDiscordInstall()
@@ -45,11 +61,9 @@ func (discord *DiscordInstall) InstallBD() error {
 	output.Blank()
 
 	// Terminate and restart Discord if possible
-	output.Printf("🔄 Restarting %s...\n", discord.Channel.Name())
-	if err := discord.restart(); err != nil {
+	if err := discord.maybeRestart(); err != nil {
 		return err
 	}
-	output.Blank()
 
 	return nil
 }
@@ -62,42 +76,102 @@ func (discord *DiscordInstall) UninstallBD() error {
 	}
 	output.Blank()
 
+	if err := discord.maybeRestart(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// maybeRestart restarts Discord unless AutoRestart is disabled.
+func (discord *DiscordInstall) maybeRestart() error {
+	if !AutoRestart {
+		output.Printf("💡 Restart %s for the changes to take effect.\n", discord.Channel.Name())
+		output.Blank()
+		return nil
+	}
+
 	output.Printf("🔄 Restarting %s...\n", discord.Channel.Name())
 	if err := discord.restart(); err != nil {
 		return err
 	}
 	output.Blank()
-
 	return nil
 }
 
+// RepairOptions selects what RepairBD fixes.
+type RepairOptions struct {
+	// Redownload replaces betterdiscord.asar with a fresh download.
+	Redownload bool
+	// Reinject restores the original index.js and injects into it again.
+	Reinject bool
+	// ResetPlugins removes plugins.json, disabling every plugin for the channel.
+	ResetPlugins bool
+	// DisablePlugins switches off only these plugins in plugins.json.
+	DisablePlugins []string
+}
+
 // RepairBD repairs BetterDiscord for this Discord installation
-func (discord *DiscordInstall) RepairBD() error {
-	if err := discord.UninstallBD(); err != nil {
-		return err
-	}
+func (discord *DiscordInstall) RepairBD(options RepairOptions) error {
+	bd := discord.GetBetterDiscordInstall()
 
-	// Gets the global BetterDiscord install
-	bd := betterdiscord.GetInstallation()
+	if options.Redownload {
+		output.Println("📥 Downloading BetterDiscord...")
+		if err := bd.Prepare(); err != nil {
+			return err
+		}
+		if err := bd.Download(); err != nil {
+			return err
+		}
+		output.Blank()
+	}
 
-	// Snaps get their own local BD install
-	if discord.IsSnap {
-		bd = betterdiscord.GetInstallation(filepath.Clean(filepath.Join(discord.CorePath, "..", "..", "..", "..")))
+	if options.Reinject {
+		// Put Discord's own index.js back first so the injection is written
+		// over the original and a fresh snapshot is taken of it
+		output.Println("🧹 Restoring the original index.js...")
+		if discord.IndexUnrecognized() {
+			output.Printf("⚠️  index.js in %s was replaced since BetterDiscord was injected, keeping it as the new original\n", discord.CorePath)
+		}
+		if err := discord.uninject(); err != nil {
+			return err
+		}
+		output.Println("🔌 Injecting into Discord...")
+		if err := discord.inject(bd); err != nil {
+			return err
+		}
+		output.Blank()
 	}
 
-	if err := bd.Repair(discord.Channel); err != nil {
-		return err
+	if options.ResetPlugins {
+		output.Println("🧹 Resetting plugins...")
+		if err := bd.Repair(discord.Channel); err != nil {
+			return err
+		}
+		output.Blank()
+	} else if len(options.DisablePlugins) > 0 {
+		output.Println("🧹 Disabling plugins...")
+		names, err := bd.SetAddonsEnabled(betterdiscord.AddonPlugin, discord.Channel, options.DisablePlugins, false)
+		if err != nil {
+			output.Printf("❌ Unable to update %s\n", bd.AddonStatesPath(betterdiscord.AddonPlugin, discord.Channel))
+			output.Printf("   %s\n", err.Error())
+			return err
+		}
+		for _, name := range names {
+			output.Printf("✅ Disabled %s for %s\n", name, discord.Channel.Name())
+		}
+		output.Blank()
 	}
 
-	return nil
+	return discord.maybeRestart()
 }
 
 func (discord *DiscordInstall) GetBetterDiscordInstall() *betterdiscord.BDInstall {
 	// Gets the global BetterDiscord install
 	bd := betterdiscord.GetInstallation()
 
-	// Snaps get their own local BD install
-	if discord.IsSnap {
+	// Snaps get their own local BD install, unless a custom root was chosen
+	if discord.IsSnap && !betterdiscord.HasRootOverride() {
 		bd = betterdiscord.GetInstallation(filepath.Clean(filepath.Join(discord.CorePath, "..", "..", "..", "..")))
 	}
 
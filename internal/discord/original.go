@@ -0,0 +1,101 @@
+package discord
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/betterdiscord/cli/internal/betterdiscord"
+	"github.com/betterdiscord/cli/internal/dryrun"
+	"github.com/betterdiscord/cli/internal/utils"
+)
+
+// OriginalIndex describes the index.js snapshot taken before injecting.
+type OriginalIndex struct {
+	CorePath string    `json:"corePath"`
+	SHA256   string    `json:"sha256"`
+	Saved    time.Time `json:"saved"`
+}
+
+// OriginalIndex returns the snapshot of Discord's own index.js saved when
+// BetterDiscord was injected, or nil if there is none.
+func (discord *DiscordInstall) OriginalIndex() *OriginalIndex {
+	contents, err := os.ReadFile(filepath.Join(discord.originalDir(discord.GetBetterDiscordInstall()), "original.json"))
+	if err != nil {
+		return nil
+	}
+	var original OriginalIndex
+	if err := json.Unmarshal(contents, &original); err != nil {
+		return nil
+	}
+	return &original
+}
+
+// IndexUnrecognized reports whether index.js is neither a BetterDiscord
+// injection nor the snapshot saved at install time, which usually means
+// another tool or a Discord update replaced it.
+func (discord *DiscordInstall) IndexUnrecognized() bool {
+	original := discord.OriginalIndex()
+	if original == nil || discord.InjectionStatus().State != InjectionNone {
+		return false
+	}
+	hash, err := utils.SHA256File(filepath.Join(discord.CorePath, "index.js"))
+	if err != nil {
+		return false
+	}
+	return hash != original.SHA256
+}
+
+// originalDir is where Discord's own index.js for this install is kept,
+// keyed by the core path since several installs can share one BetterDiscord folder.
+func (discord *DiscordInstall) originalDir(bd *betterdiscord.BDInstall) string {
+	sum := sha256.Sum256([]byte(discord.CorePath))
+	return filepath.Join(bd.Data(), "originals", hex.EncodeToString(sum[:8]))
+}
+
+// saveOriginal snapshots contents along with its hash.
+func (discord *DiscordInstall) saveOriginal(bd *betterdiscord.BDInstall, contents []byte) error {
+	dir := discord.originalDir(bd)
+	if dryrun.Skip(dryrun.WriteFile, filepath.Join(dir, "index.js"), "snapshot of the original index.js") {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.js"), contents, 0644); err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(contents)
+	metadata, err := json.MarshalIndent(OriginalIndex{
+		CorePath: discord.CorePath,
+		SHA256:   hex.EncodeToString(sum[:]),
+		Saved:    time.Now().UTC().Truncate(time.Second),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "original.json"), metadata, 0644)
+}
+
+// readOriginal returns the snapshot, refusing it if it no longer matches its hash.
+func (discord *DiscordInstall) readOriginal(bd *betterdiscord.BDInstall) ([]byte, error) {
+	original := discord.OriginalIndex()
+	if original == nil {
+		return nil, fmt.Errorf("no snapshot of index.js was saved")
+	}
+
+	snapshot := filepath.Join(discord.originalDir(bd), "index.js")
+	hash, err := utils.SHA256File(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if hash != original.SHA256 {
+		return nil, fmt.Errorf("snapshot %s does not match its recorded hash", snapshot)
+	}
+	return os.ReadFile(snapshot)
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/betterdiscord/cli/internal/models"
+	"github.com/betterdiscord/cli/internal/semver"
 )
 
 var searchPaths []string
@@ -27,6 +28,26 @@ func GetAllInstalls() map[models.DiscordChannel][]*DiscordInstall {
 	return allDiscordInstalls
 }
 
+// AddSearchPaths registers additional folders to look for Discord installs in
+// and refreshes the detected installs if any of them are new.
+func AddSearchPaths(paths ...string) {
+	added := false
+	for _, path := range paths {
+		if path != "" && !slices.Contains(searchPaths, path) {
+			searchPaths = append(searchPaths, path)
+			added = true
+		}
+	}
+	if added {
+		GetAllInstalls()
+	}
+}
+
+// SearchPaths returns the folders searched for Discord installs.
+func SearchPaths() []string {
+	return slices.Clone(searchPaths)
+}
+
 func GetVersion(proposed string) string {
 	for folder := range strings.SplitSeq(proposed, string(filepath.Separator)) {
 		if version := versionRegex.FindString(folder); version != "" {
@@ -54,6 +75,18 @@ func GetSuggestedPath(channel models.DiscordChannel) string {
 	return ""
 }
 
+// GetInstalls returns the detected installs of channel, newest first. When
+// installType is not empty only installs of that type are returned.
+func GetInstalls(channel models.DiscordChannel, installType string) []*DiscordInstall {
+	var installs []*DiscordInstall
+	for _, install := range allDiscordInstalls[channel] {
+		if installType == "" || install.Type() == installType {
+			installs = append(installs, install)
+		}
+	}
+	return installs
+}
+
 func AddCustomPath(proposed string) *DiscordInstall {
 	result := Validate(proposed)
 	if result == nil {
@@ -85,16 +118,12 @@ func ResolvePath(proposed string) *DiscordInstall {
 	return AddCustomPath(proposed)
 }
 
+// sortInstalls orders the installs of each channel newest first. Installs
+// of the same version keep the order of the search paths they came from.
 func sortInstalls() {
 	for channel := range allDiscordInstalls {
-		slices.SortFunc(allDiscordInstalls[channel], func(a, b *DiscordInstall) int {
-			switch {
-			case a.Version > b.Version:
-				return -1
-			case b.Version > a.Version:
-				return 1
-			}
-			return 0
+		slices.SortStableFunc(allDiscordInstalls[channel], func(a, b *DiscordInstall) int {
+			return semver.Compare(b.Version, a.Version)
 		})
 	}
 }
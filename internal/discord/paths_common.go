@@ -9,9 +9,16 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/betterdiscord/cli/internal/semver"
 	"github.com/betterdiscord/cli/internal/utils"
 )
 
+// compareVersionDirs compares version folders such as app-1.0.9002 or
+// 0.0.35 numerically, so 0.0.100 sorts after 0.0.99.
+func compareVersionDirs(a, b fs.DirEntry) int {
+	return semver.Compare(GetVersion(a.Name()), GetVersion(b.Name()))
+}
+
 // validateWindowsStyleInstall validates a Windows-style Discord installation path.
 // This is used for native Windows installs and WSL installs that point to Windows Discord.
 // Windows Discord has a nested structure: Discord/app-1.0.9002/modules/discord_desktop_core-1/discord_desktop_core
@@ -32,7 +39,7 @@ func validateWindowsStyleInstall(proposed string) *DiscordInstall {
 		if len(candidates) == 0 {
 			return nil
 		}
-		sort.Slice(candidates, func(i, j int) bool { return candidates[i].Name() < candidates[j].Name() })
+		sort.Slice(candidates, func(i, j int) bool { return compareVersionDirs(candidates[i], candidates[j]) < 0 })
 		versionDir := candidates[len(candidates)-1].Name()
 
 		// Get core wrap like discord_desktop_core-1
@@ -105,7 +112,7 @@ func validateUnixStyleInstall(proposed string, detectFlatpak bool, detectSnap bo
 		if len(candidates) == 0 {
 			return nil
 		}
-		sort.Slice(candidates, func(i, j int) bool { return candidates[i].Name() < candidates[j].Name() })
+		sort.Slice(candidates, func(i, j int) bool { return compareVersionDirs(candidates[i], candidates[j]) < 0 })
 		versionDir := candidates[len(candidates)-1].Name()
 		finalPath = filepath.Join(proposed, versionDir, "modules", "discord_desktop_core")
 	}
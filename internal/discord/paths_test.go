@@ -1,10 +1,14 @@
 package discord
 
 import (
+	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"testing"
 
 	"github.com/betterdiscord/cli/internal/models"
+	"github.com/betterdiscord/cli/internal/wsl"
 )
 
 func TestGetVersion(t *testing.T) {
@@ -296,3 +300,74 @@ func TestSortInstalls_MultipleChannels(t *testing.T) {
 		t.Errorf("Canary: Third version should be 0.0.100, got %s", canaryInstalls[2].Version)
 	}
 }
+
+func TestSortInstalls_NumericVersions(t *testing.T) {
+	allDiscordInstalls = map[models.DiscordChannel][]*DiscordInstall{
+		models.Canary: {
+			{CorePath: "/canary/native-old", Version: "0.0.99", Channel: models.Canary},
+			{CorePath: "/canary/native", Version: "0.0.100", Channel: models.Canary},
+			{CorePath: "/canary/flatpak", Version: "0.0.100", Channel: models.Canary, IsFlatpak: true},
+			{CorePath: "/canary/snap", Version: "0.0.9", Channel: models.Canary, IsSnap: true},
+		},
+	}
+
+	sortInstalls()
+
+	var paths []string
+	for _, install := range allDiscordInstalls[models.Canary] {
+		paths = append(paths, install.CorePath)
+	}
+	expected := []string{"/canary/native", "/canary/flatpak", "/canary/native-old", "/canary/snap"}
+	if !slices.Equal(paths, expected) {
+		t.Errorf("sortInstalls() order = %v, expected %v", paths, expected)
+	}
+}
+
+func TestGetInstalls(t *testing.T) {
+	native := &DiscordInstall{CorePath: "/native", Version: "0.0.40", Channel: models.Stable}
+	flatpak := &DiscordInstall{CorePath: "/flatpak", Version: "0.0.39", Channel: models.Stable, IsFlatpak: true}
+	snap := &DiscordInstall{CorePath: "/snap", Version: "0.0.38", Channel: models.Stable, IsSnap: true}
+	allDiscordInstalls = map[models.DiscordChannel][]*DiscordInstall{
+		models.Stable: {native, flatpak, snap},
+	}
+
+	tests := []struct {
+		installType string
+		expected    []*DiscordInstall
+	}{
+		{"", []*DiscordInstall{native, flatpak, snap}},
+		{"native", []*DiscordInstall{native}},
+		{"flatpak", []*DiscordInstall{flatpak}},
+		{"snap", []*DiscordInstall{snap}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.installType, func(t *testing.T) {
+			if got := GetInstalls(models.Stable, tt.installType); !slices.Equal(got, tt.expected) {
+				t.Errorf("GetInstalls(Stable, %q) = %v, expected %v", tt.installType, got, tt.expected)
+			}
+		})
+	}
+
+	if got := GetInstalls(models.PTB, ""); len(got) != 0 {
+		t.Errorf("GetInstalls(PTB) = %v, expected none", got)
+	}
+}
+
+func TestValidate_NewestVersionFolder(t *testing.T) {
+	if runtime.GOOS == "windows" || wsl.IsWSL() {
+		t.Skip("fixtures use the Unix install layout")
+	}
+
+	root := filepath.Join(t.TempDir(), "discord")
+	for _, version := range []string{"0.0.99", "0.0.100"} {
+		corePath := filepath.Join(root, version, "modules", "discord_desktop_core")
+		os.MkdirAll(corePath, 0755)                                              //nolint:errcheck
+		os.WriteFile(filepath.Join(corePath, "core.asar"), []byte("asar"), 0644) //nolint:errcheck
+	}
+
+	install := Validate(root)
+	if install == nil || install.Version != "0.0.100" {
+		t.Errorf("Validate() = %+v, expected the 0.0.100 install", install)
+	}
+}
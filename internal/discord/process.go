@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strings"
 
+	"github.com/betterdiscord/cli/internal/dryrun"
 	"github.com/betterdiscord/cli/internal/output"
 	"github.com/shirou/gopsutil/v3/process"
 )
@@ -17,10 +19,12 @@ func (discord *DiscordInstall) restart() error {
 		return nil
 	}
 
-	if err := discord.kill(); err != nil {
-		output.Printf("❌ Unable to restart %s, please do so manually.\n", discord.Channel.Name())
-		output.Printf("   %s\n", err.Error())
-		return err
+	if !dryrun.Skip(dryrun.Kill, discord.Channel.Exe(), "") {
+		if err := discord.kill(); err != nil {
+			output.Printf("❌ Unable to restart %s, please do so manually.\n", discord.Channel.Name())
+			output.Printf("   %s\n", err.Error())
+			return err
+		}
 	}
 
 	// Determine command based on installation type
@@ -38,6 +42,10 @@ func (discord *DiscordInstall) restart() error {
 		cmd = exec.Command(exeName)
 	}
 
+	if dryrun.Skip(dryrun.Start, strings.Join(cmd.Args, " "), "") {
+		return nil
+	}
+
 	// Set working directory to user home
 	cmd.Dir, _ = os.UserHomeDir()
 
@@ -0,0 +1,206 @@
+package discord
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/betterdiscord/cli/internal/models"
+)
+
+// DefaultWatchDelay is how long the watcher waits for Discord to finish
+// writing an update before inspecting it.
+const DefaultWatchDelay = 2 * time.Second
+
+// maxWatchDepth is how far below a search path new folders are watched.
+// Windows-style installs keep the core at app-*/modules/discord_desktop_core-*/discord_desktop_core.
+const maxWatchDepth = 4
+
+// WatchAction is what the watcher did about an install.
+type WatchAction string
+
+const (
+	WatchReinjected WatchAction = "reinjected"
+	WatchFailed     WatchAction = "failed"
+	// WatchError reports a filesystem notification error. Watching continues.
+	WatchError WatchAction = "error"
+)
+
+// WatchEvent is reported every time the watcher acts on a new core folder,
+// and for errors from the filesystem notifications.
+type WatchEvent struct {
+	Time      time.Time             `json:"time"`
+	Action    WatchAction           `json:"action"`
+	Channel   models.DiscordChannel `json:"channel"`
+	Version   string                `json:"version"`
+	CorePath  string                `json:"corePath"`
+	Restarted bool                  `json:"restarted"`
+	Error     string                `json:"error,omitempty"`
+}
+
+// Watcher re-injects BetterDiscord when Discord updates itself into a new
+// versioned folder. Only search paths whose newest install was injected when
+// the watcher was created are tracked, so installs the user never injected
+// or deliberately uninstalled are left alone.
+type Watcher struct {
+	// Delay is how long to wait after the last filesystem event before checking.
+	Delay time.Duration
+	// Restart restarts Discord after a successful re-injection.
+	Restart bool
+	// OnEvent is called for every re-injection attempt and watch error.
+	OnEvent func(WatchEvent)
+
+	// tracked maps each search path to the last core folder seen in it
+	tracked map[string]*DiscordInstall
+}
+
+// NewWatcher tracks every root whose current Discord install is injected.
+func NewWatcher(roots []string) *Watcher {
+	w := &Watcher{Delay: DefaultWatchDelay, tracked: map[string]*DiscordInstall{}}
+	for _, root := range roots {
+		if install := Validate(root); install != nil && install.IsInjected() {
+			w.tracked[root] = install
+		}
+	}
+	return w
+}
+
+// Installs returns the installs being watched, sorted by core path.
+func (w *Watcher) Installs() []*DiscordInstall {
+	installs := make([]*DiscordInstall, 0, len(w.tracked))
+	for _, install := range w.tracked {
+		installs = append(installs, install)
+	}
+	slices.SortFunc(installs, func(a, b *DiscordInstall) int { return strings.Compare(a.CorePath, b.CorePath) })
+	return installs
+}
+
+// Check looks for new core folders in the tracked roots and injects any
+// that are missing BetterDiscord.
+func (w *Watcher) Check() {
+	for _, root := range slices.Sorted(maps.Keys(w.tracked)) {
+		install := Validate(root)
+		if install == nil || install.CorePath == w.tracked[root].CorePath {
+			continue
+		}
+
+		// Discord writes index.js last, wait for it rather than racing the updater
+		if _, err := os.Stat(filepath.Join(install.CorePath, "index.js")); err != nil {
+			continue
+		}
+		w.tracked[root] = install
+		if install.IsInjected() {
+			continue
+		}
+
+		event := WatchEvent{
+			Time:     time.Now(),
+			Action:   WatchReinjected,
+			Channel:  install.Channel,
+			Version:  install.Version,
+			CorePath: install.CorePath,
+		}
+		if err := install.inject(install.GetBetterDiscordInstall()); err != nil {
+			event.Action = WatchFailed
+			event.Error = err.Error()
+		} else if w.Restart {
+			if err := install.restart(); err != nil {
+				event.Error = err.Error()
+			} else {
+				event.Restarted = true
+			}
+		}
+		if w.OnEvent != nil {
+			w.OnEvent(event)
+		}
+	}
+}
+
+// Run watches the tracked roots until ctx is cancelled. Notification errors,
+// such as an overflowing event queue, are reported and watching continues.
+func (w *Watcher) Run(ctx context.Context) error {
+	notify, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer notify.Close() //nolint:errcheck
+	return w.watch(ctx, notify)
+}
+
+func (w *Watcher) watch(ctx context.Context, notify *fsnotify.Watcher) error {
+	for root := range w.tracked {
+		w.addTree(notify, root, 0)
+	}
+
+	timer := time.NewTimer(w.Delay)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-notify.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					w.addTree(notify, event.Name, w.depth(event.Name))
+				}
+			}
+			timer.Reset(w.Delay)
+		case err, ok := <-notify.Errors:
+			if !ok {
+				return nil
+			}
+			if w.OnEvent != nil {
+				w.OnEvent(WatchEvent{Time: time.Now(), Action: WatchError, Error: err.Error()})
+			}
+			// Events may have been dropped, so look for new core folders anyway
+			timer.Reset(w.Delay)
+		case <-timer.C:
+			w.Check()
+		}
+	}
+}
+
+// addTree watches dir and its subfolders down to maxWatchDepth.
+func (w *Watcher) addTree(notify *fsnotify.Watcher, dir string, depth int) {
+	if depth > maxWatchDepth {
+		return
+	}
+	if err := notify.Add(dir); err != nil {
+		return
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			w.addTree(notify, filepath.Join(dir, entry.Name()), depth+1)
+		}
+	}
+}
+
+// depth returns how many folders path is below the tracked root containing it.
+func (w *Watcher) depth(path string) int {
+	for root := range w.tracked {
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if rel == "." {
+			return 0
+		}
+		return strings.Count(rel, string(filepath.Separator)) + 1
+	}
+	return maxWatchDepth + 1
+}
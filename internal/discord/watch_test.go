@@ -0,0 +1,199 @@
+package discord
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/betterdiscord/cli/internal/betterdiscord"
+	"github.com/betterdiscord/cli/internal/wsl"
+)
+
+// makeCore creates a Unix-style Discord core folder for version under root.
+func makeCore(t *testing.T, root, version string) *DiscordInstall {
+	t.Helper()
+	corePath := filepath.Join(root, version, "modules", "discord_desktop_core")
+	os.MkdirAll(corePath, 0755)                                                   //nolint:errcheck
+	os.WriteFile(filepath.Join(corePath, "core.asar"), []byte("asar"), 0644)      //nolint:errcheck
+	os.WriteFile(filepath.Join(corePath, "index.js"), []byte(defaultIndex), 0644) //nolint:errcheck
+	install := Validate(root)
+	if install == nil || install.CorePath != corePath {
+		t.Fatalf("Validate(%s) = %+v, expected the %s core", root, install, version)
+	}
+	return install
+}
+
+func setupWatchFixture(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" || wsl.IsWSL() {
+		t.Skip("fixtures use the Unix install layout")
+	}
+	betterdiscord.SetRoot(filepath.Join(t.TempDir(), "BetterDiscord"))
+	t.Cleanup(func() { betterdiscord.SetRoot("") })
+	return filepath.Join(t.TempDir(), "discord")
+}
+
+func TestWatcher_ReinjectsNewCore(t *testing.T) {
+	root := setupWatchFixture(t)
+	old := makeCore(t, root, "0.0.35")
+	if err := old.inject(old.GetBetterDiscordInstall()); err != nil {
+		t.Fatalf("inject() failed: %v", err)
+	}
+
+	w := NewWatcher([]string{root})
+	if installs := w.Installs(); len(installs) != 1 || installs[0].CorePath != old.CorePath {
+		t.Fatalf("Installs() = %+v, expected the injected 0.0.35 core", installs)
+	}
+
+	var events []WatchEvent
+	w.OnEvent = func(event WatchEvent) { events = append(events, event) }
+
+	w.Check()
+	if len(events) != 0 {
+		t.Fatalf("Check() without changes reported %+v", events)
+	}
+
+	updated := makeCore(t, root, "0.0.36")
+	w.Check()
+	if len(events) != 1 {
+		t.Fatalf("Check() reported %d events, expected 1", len(events))
+	}
+	if events[0].Action != WatchReinjected || events[0].CorePath != updated.CorePath || events[0].Version != "0.0.36" {
+		t.Errorf("Check() reported %+v", events[0])
+	}
+	if state := updated.InjectionStatus().State; state != InjectionCurrent {
+		t.Errorf("new core injection = %s, expected current", state)
+	}
+
+	w.Check()
+	if len(events) != 1 {
+		t.Errorf("Check() re-injected an already handled core")
+	}
+}
+
+func TestWatcher_LeavesUninjectedInstalls(t *testing.T) {
+	root := setupWatchFixture(t)
+	makeCore(t, root, "0.0.35")
+
+	w := NewWatcher([]string{root, filepath.Join(t.TempDir(), "missing")})
+	if installs := w.Installs(); len(installs) != 0 {
+		t.Fatalf("Installs() = %+v, expected nothing to watch", installs)
+	}
+
+	makeCore(t, root, "0.0.36")
+	w.OnEvent = func(event WatchEvent) { t.Errorf("unexpected event %+v", event) }
+	w.Check()
+}
+
+func TestWatcher_RespectsUninstall(t *testing.T) {
+	root := setupWatchFixture(t)
+	install := makeCore(t, root, "0.0.35")
+	if err := install.inject(install.GetBetterDiscordInstall()); err != nil {
+		t.Fatalf("inject() failed: %v", err)
+	}
+
+	w := NewWatcher([]string{root})
+	w.OnEvent = func(event WatchEvent) { t.Errorf("unexpected event %+v", event) }
+
+	// Uninstalling from the same core is deliberate and must not be undone
+	if err := install.uninject(); err != nil {
+		t.Fatalf("uninject() failed: %v", err)
+	}
+	w.Check()
+	if install.IsInjected() {
+		t.Error("Check() re-injected a core the user uninstalled from")
+	}
+}
+
+func TestWatcher_Run(t *testing.T) {
+	root := setupWatchFixture(t)
+	old := makeCore(t, root, "0.0.35")
+	if err := old.inject(old.GetBetterDiscordInstall()); err != nil {
+		t.Fatalf("inject() failed: %v", err)
+	}
+
+	received := make(chan WatchEvent, 1)
+	w := NewWatcher([]string{root})
+	w.Delay = 50 * time.Millisecond
+	w.OnEvent = func(event WatchEvent) { received <- event }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+
+	// Give the watcher time to register the tree before Discord "updates"
+	time.Sleep(100 * time.Millisecond)
+	updated := makeCore(t, root, "0.0.36")
+
+	select {
+	case event := <-received:
+		if event.CorePath != updated.CorePath || event.Action != WatchReinjected {
+			t.Errorf("Run() reported %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not re-inject the new core")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run() returned %v", err)
+	}
+}
+
+func TestWatcher_KeepsWatchingAfterErrors(t *testing.T) {
+	root := setupWatchFixture(t)
+	old := makeCore(t, root, "0.0.35")
+	if err := old.inject(old.GetBetterDiscordInstall()); err != nil {
+		t.Fatalf("inject() failed: %v", err)
+	}
+
+	notify, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatalf("NewWatcher() failed: %v", err)
+	}
+	defer notify.Close() //nolint:errcheck
+	errs := make(chan error)
+	notify.Errors = errs
+
+	received := make(chan WatchEvent, 2)
+	w := NewWatcher([]string{root})
+	w.Delay = 50 * time.Millisecond
+	w.OnEvent = func(event WatchEvent) { received <- event }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.watch(ctx, notify) }()
+
+	errs <- fsnotify.ErrEventOverflow
+	select {
+	case event := <-received:
+		if event.Action != WatchError || event.Error != fsnotify.ErrEventOverflow.Error() {
+			t.Errorf("watch() reported %+v, expected the overflow error", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch() did not report the error")
+	}
+
+	// Still watching: an update after the error is re-injected
+	updated := makeCore(t, root, "0.0.36")
+	select {
+	case event := <-received:
+		if event.CorePath != updated.CorePath || event.Action != WatchReinjected {
+			t.Errorf("watch() reported %+v", event)
+		}
+	case err := <-done:
+		t.Fatalf("watch() stopped after an error: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch() did not re-inject the new core after an error")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("watch() returned %v", err)
+	}
+}
@@ -0,0 +1,365 @@
+// Package doctor runs diagnostic checks against the Discord installs and
+// the BetterDiscord folder and explains how to fix what it finds.
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/betterdiscord/cli/internal/betterdiscord"
+	"github.com/betterdiscord/cli/internal/discord"
+	"github.com/betterdiscord/cli/internal/dryrun"
+	"github.com/betterdiscord/cli/internal/models"
+	"github.com/betterdiscord/cli/internal/semver"
+	"github.com/betterdiscord/cli/internal/utils"
+	"github.com/betterdiscord/cli/internal/wsl"
+)
+
+// Status is the outcome of a single check.
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// Result is the outcome of a check along with a hint on how to fix it.
+type Result struct {
+	Check   string `json:"check"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// Report collects the results of every check that applied.
+type Report struct {
+	Results  []Result `json:"results"`
+	Passed   int      `json:"passed"`
+	Warnings int      `json:"warnings"`
+	Failures int      `json:"failures"`
+}
+
+func (r *Report) add(results ...Result) {
+	for _, result := range results {
+		switch result.Status {
+		case StatusPass:
+			r.Passed++
+		case StatusWarn:
+			r.Warnings++
+		case StatusFail:
+			r.Failures++
+		}
+		r.Results = append(r.Results, result)
+	}
+}
+
+// Run executes every check. Checks that do not apply to this machine, such
+// as flatpak checks without flatpak installs, are left out of the report.
+func Run() *Report {
+	report := &Report{}
+	installs := flattenInstalls(discord.GetAllInstalls())
+	bd := betterdiscord.GetInstallation()
+
+	report.add(checkInstalls(installs))
+	report.add(checkInjections(installs)...)
+	report.add(checkAsar(bd)...)
+	report.add(checkAddonFolder("plugins folder", bd.Plugins()))
+	report.add(checkAddonFolder("themes folder", bd.Themes()))
+	report.add(checkFlatpak(installs)...)
+	report.add(checkSnap(installs)...)
+	if wsl.IsWSL() {
+		report.add(checkWSL())
+	}
+	report.add(checkDuplicates(betterdiscord.AddonPlugin))
+	report.add(checkDuplicates(betterdiscord.AddonTheme))
+	return report
+}
+
+func flattenInstalls(all map[models.DiscordChannel][]*discord.DiscordInstall) []*discord.DiscordInstall {
+	var installs []*discord.DiscordInstall
+	for _, channel := range models.Channels {
+		installs = append(installs, all[channel]...)
+	}
+	return installs
+}
+
+func checkInstalls(installs []*discord.DiscordInstall) Result {
+	if len(installs) == 0 {
+		return Result{
+			Check:   "discord installs",
+			Status:  StatusFail,
+			Message: "no Discord installations detected",
+			Hint:    "Install Discord, or add its location with 'bdcli config set discord.paths <path>'",
+		}
+	}
+	return Result{Check: "discord installs", Status: StatusPass, Message: fmt.Sprintf("%d installation(s) detected", len(installs))}
+}
+
+func checkInjections(installs []*discord.DiscordInstall) []Result {
+	var results []Result
+	for _, inst := range installs {
+		check := "injection " + inst.Channel.String() + " " + inst.Version
+		status := inst.InjectionStatus()
+		switch {
+		case status.State == discord.InjectionCurrent:
+			results = append(results, Result{Check: check, Status: StatusPass, Message: "injection is current"})
+		case status.State == discord.InjectionStale:
+			results = append(results, Result{
+				Check:   check,
+				Status:  StatusWarn,
+				Message: "injection is stale: " + status.Reason,
+				Hint:    fmt.Sprintf("Run 'bdcli install --path %s' to upgrade it", inst.CorePath),
+			})
+		case inst.IndexUnrecognized():
+			results = append(results, Result{
+				Check:   check,
+				Status:  StatusWarn,
+				Message: "index.js matches neither the injection nor the original saved at install",
+				Hint:    fmt.Sprintf("Run 'bdcli install --path %s' to inject again", inst.CorePath),
+			})
+		default:
+			results = append(results, Result{
+				Check:   check,
+				Status:  StatusWarn,
+				Message: "BetterDiscord is not injected",
+				Hint:    fmt.Sprintf("Run 'bdcli install --path %s' to inject", inst.CorePath),
+			})
+		}
+	}
+	return results
+}
+
+func checkAsar(bd *betterdiscord.BDInstall) []Result {
+	if !bd.IsAsarInstalled() {
+		return []Result{{
+			Check:   "betterdiscord.asar",
+			Status:  StatusFail,
+			Message: "not found at " + bd.Asar(),
+			Hint:    "Run 'bdcli install' to download it",
+		}}
+	}
+
+	buildinfo, err := bd.ReadBuildinfo()
+	if err != nil {
+		return []Result{{
+			Check:   "betterdiscord.asar",
+			Status:  StatusFail,
+			Message: "unable to read build info: " + err.Error(),
+			Hint:    "Run 'bdcli update' to download a fresh copy",
+		}}
+	}
+
+	results := []Result{{Check: "betterdiscord.asar", Status: StatusPass, Message: "version " + buildinfo.Version}}
+	return append(results, checkLatest(buildinfo.Version))
+}
+
+func checkLatest(current string) Result {
+	if betterdiscord.StoreCache.Offline {
+		return Result{Check: "betterdiscord version", Status: StatusWarn, Message: "skipped in offline mode"}
+	}
+
+	release, err := betterdiscord.FetchLatestRelease()
+	if err != nil {
+		return Result{
+			Check:   "betterdiscord version",
+			Status:  StatusWarn,
+			Message: "unable to check the latest release: " + err.Error(),
+		}
+	}
+
+	if semver.Compare(current, release.TagName) < 0 {
+		return Result{
+			Check:   "betterdiscord version",
+			Status:  StatusWarn,
+			Message: fmt.Sprintf("%s is available, %s is installed", release.TagName, current),
+			Hint:    "Run 'bdcli update'",
+		}
+	}
+	return Result{Check: "betterdiscord version", Status: StatusPass, Message: "up to date"}
+}
+
+func checkAddonFolder(check, dir string) Result {
+	if !utils.Exists(dir) {
+		return Result{
+			Check:   check,
+			Status:  StatusWarn,
+			Message: dir + " does not exist",
+			Hint:    "Run 'bdcli install' to create it",
+		}
+	}
+
+	// The write probe creates a file, which dry-run mode promises not to do
+	if dryrun.Enabled() {
+		return Result{Check: check, Status: StatusWarn, Message: "write check skipped in dry-run mode"}
+	}
+
+	if err := probeWritable(dir); err != nil {
+		return Result{
+			Check:   check,
+			Status:  StatusFail,
+			Message: dir + " is not writable: " + err.Error(),
+			Hint:    "Fix the permissions or ownership of " + dir,
+		}
+	}
+	return Result{Check: check, Status: StatusPass, Message: dir + " is writable"}
+}
+
+// probeWritable creates and removes a temporary file in dir.
+func probeWritable(dir string) error {
+	file, err := os.CreateTemp(dir, ".bdcli-doctor-*")
+	if err != nil {
+		return err
+	}
+	file.Close() //nolint:errcheck
+	return os.Remove(file.Name())
+}
+
+func checkFlatpak(installs []*discord.DiscordInstall) []Result {
+	var results []Result
+	for _, inst := range installs {
+		if !inst.IsFlatpak {
+			continue
+		}
+
+		check := "flatpak override " + inst.Channel.String()
+		id := "com.discordapp." + inst.Channel.Exe()
+		root := inst.GetBetterDiscordInstall().Root()
+		hint := fmt.Sprintf("Run 'flatpak --user override %s --filesystem=%s'", id, root)
+
+		out, err := exec.Command("flatpak", "--user", "override", "--show", id).Output()
+		if err != nil {
+			results = append(results, Result{Check: check, Status: StatusFail, Message: "unable to read flatpak overrides: " + err.Error(), Hint: hint})
+			continue
+		}
+		if !flatpakOverrideHasPath(string(out), root) {
+			results = append(results, Result{Check: check, Status: StatusFail, Message: id + " cannot access " + root, Hint: hint})
+			continue
+		}
+		results = append(results, Result{Check: check, Status: StatusPass, Message: id + " can access " + root})
+	}
+	return results
+}
+
+// flatpakOverrideHasPath reports whether the filesystems granted in the
+// output of 'flatpak override --show' include path or one of its parents.
+func flatpakOverrideHasPath(overrides, path string) bool {
+	for line := range strings.SplitSeq(overrides, "\n") {
+		value, ok := strings.CutPrefix(strings.TrimSpace(line), "filesystems=")
+		if !ok {
+			continue
+		}
+		for fs := range strings.SplitSeq(value, ";") {
+			// Entries may carry an access suffix like :ro or :create
+			fs, _, _ = strings.Cut(fs, ":")
+			switch fs {
+			case "":
+				continue
+			case "host":
+				return true
+			case "home":
+				fs, _ = os.UserHomeDir()
+			}
+			fs = filepath.Clean(fs)
+			if path == fs || strings.HasPrefix(path, fs+string(filepath.Separator)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func checkSnap(installs []*discord.DiscordInstall) []Result {
+	var results []Result
+	for _, inst := range installs {
+		if !inst.IsSnap {
+			continue
+		}
+
+		check := "snap root " + inst.Channel.String()
+		bd := inst.GetBetterDiscordInstall()
+		status := inst.InjectionStatus()
+		hint := fmt.Sprintf("Run 'bdcli install --path %s'", inst.CorePath)
+
+		switch {
+		case status.Marker != nil && status.Marker.Root != bd.Root():
+			results = append(results, Result{Check: check, Status: StatusFail, Message: fmt.Sprintf("injection points to %s instead of %s", status.Marker.Root, bd.Root()), Hint: hint})
+		case status.State != discord.InjectionNone && !bd.IsAsarInstalled():
+			results = append(results, Result{Check: check, Status: StatusFail, Message: "betterdiscord.asar is missing from " + bd.Data(), Hint: hint})
+		default:
+			results = append(results, Result{Check: check, Status: StatusPass, Message: "using " + bd.Root()})
+		}
+	}
+	return results
+}
+
+func checkWSL() Result {
+	hint := "Make sure interop is enabled in /etc/wsl.conf ([interop] enabled=true) and restart WSL"
+
+	if _, err := wsl.ExecWindows("echo bdcli"); err != nil {
+		return Result{Check: "wsl interop", Status: StatusFail, Message: "unable to run Windows commands: " + err.Error(), Hint: hint}
+	}
+	home, err := wsl.WindowsHome()
+	if err != nil {
+		return Result{Check: "wsl interop", Status: StatusFail, Message: err.Error(), Hint: hint}
+	}
+	return Result{Check: "wsl interop", Status: StatusPass, Message: "Windows home is " + home}
+}
+
+func checkDuplicates(kind betterdiscord.AddonKind) Result {
+	check := "duplicate " + string(kind) + "s"
+
+	items, err := betterdiscord.ListAddons(kind)
+	if errors.Is(err, os.ErrNotExist) {
+		return Result{Check: check, Status: StatusPass, Message: "no " + string(kind) + "s installed"}
+	}
+	if err != nil {
+		return Result{Check: check, Status: StatusWarn, Message: "unable to list " + string(kind) + "s: " + err.Error()}
+	}
+
+	duplicates := findDuplicates(items)
+	if len(duplicates) == 0 {
+		return Result{Check: check, Status: StatusPass, Message: "no duplicates"}
+	}
+
+	var groups []string
+	for _, name := range slices.Sorted(maps.Keys(duplicates)) {
+		groups = append(groups, fmt.Sprintf("%s (%s)", name, strings.Join(duplicates[name], ", ")))
+	}
+	return Result{
+		Check:   check,
+		Status:  StatusWarn,
+		Message: "multiple files share a name: " + strings.Join(groups, "; "),
+		Hint:    fmt.Sprintf("Remove the extra copies from the %ss folder", kind),
+	}
+}
+
+// findDuplicates groups the filenames of addons sharing a Meta.Name.
+func findDuplicates(items []betterdiscord.AddonEntry) map[string][]string {
+	byName := map[string][]string{}
+	display := map[string]string{}
+	for _, item := range items {
+		if item.Meta.Name == "" {
+			continue
+		}
+		key := strings.ToLower(item.Meta.Name)
+		if _, ok := display[key]; !ok {
+			display[key] = item.Meta.Name
+		}
+		byName[key] = append(byName[key], item.FullFilename)
+	}
+
+	duplicates := map[string][]string{}
+	for key, files := range byName {
+		if len(files) > 1 {
+			duplicates[display[key]] = files
+		}
+	}
+	return duplicates
+}
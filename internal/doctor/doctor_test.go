@@ -0,0 +1,111 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/betterdiscord/cli/internal/betterdiscord"
+	"github.com/betterdiscord/cli/internal/dryrun"
+)
+
+func TestReportAdd(t *testing.T) {
+	report := &Report{}
+	report.add(
+		Result{Check: "a", Status: StatusPass},
+		Result{Check: "b", Status: StatusWarn},
+		Result{Check: "c", Status: StatusFail},
+		Result{Check: "d", Status: StatusPass},
+	)
+
+	if report.Passed != 2 || report.Warnings != 1 || report.Failures != 1 {
+		t.Errorf("counts = %d/%d/%d, want 2/1/1", report.Passed, report.Warnings, report.Failures)
+	}
+	if len(report.Results) != 4 {
+		t.Errorf("len(Results) = %d, want 4", len(report.Results))
+	}
+}
+
+func TestFlatpakOverrideHasPath(t *testing.T) {
+	home, _ := os.UserHomeDir()
+	root := filepath.Join(home, ".config", "BetterDiscord")
+
+	tests := []struct {
+		name      string
+		overrides string
+		want      bool
+	}{
+		{"exact", "[Context]\nfilesystems=" + root + ";\n", true},
+		{"parent", "[Context]\nfilesystems=" + filepath.Dir(root) + ";\n", true},
+		{"read only", "[Context]\nfilesystems=" + root + ":ro;\n", true},
+		{"host", "[Context]\nfilesystems=host;\n", true},
+		{"home", "[Context]\nfilesystems=home;\n", true},
+		{"sibling prefix", "[Context]\nfilesystems=" + root + "Old;\n", false},
+		{"other path", "[Context]\nfilesystems=/tmp;xdg-download;\n", false},
+		{"no filesystems", "[Context]\nshared=network;\n", false},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := flatpakOverrideHasPath(tt.overrides, root); got != tt.want {
+				t.Errorf("flatpakOverrideHasPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	items := []betterdiscord.AddonEntry{
+		{FullFilename: "Example.plugin.js", Meta: betterdiscord.Meta{Name: "Example"}},
+		{FullFilename: "Example (1).plugin.js", Meta: betterdiscord.Meta{Name: "example"}},
+		{FullFilename: "Other.plugin.js", Meta: betterdiscord.Meta{Name: "Other"}},
+		{FullFilename: "Unnamed.plugin.js"},
+		{FullFilename: "Unnamed2.plugin.js"},
+	}
+
+	duplicates := findDuplicates(items)
+	if len(duplicates) != 1 {
+		t.Fatalf("findDuplicates() = %v, want one group", duplicates)
+	}
+	files := duplicates["Example"]
+	if !slices.Equal(files, []string{"Example.plugin.js", "Example (1).plugin.js"}) {
+		t.Errorf("duplicates[Example] = %v", files)
+	}
+}
+
+func TestCheckAddonFolder(t *testing.T) {
+	dir := t.TempDir()
+
+	if result := checkAddonFolder("plugins folder", dir); result.Status != StatusPass {
+		t.Errorf("writable folder status = %s, want pass (%s)", result.Status, result.Message)
+	}
+
+	missing := filepath.Join(dir, "missing")
+	result := checkAddonFolder("plugins folder", missing)
+	if result.Status != StatusWarn {
+		t.Errorf("missing folder status = %s, want warn", result.Status)
+	}
+	if result.Hint == "" {
+		t.Error("missing folder should include a hint")
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("probe left files behind: %v", entries)
+	}
+}
+
+func TestCheckAddonFolder_DryRun(t *testing.T) {
+	dryrun.Enable(true)
+	t.Cleanup(func() { dryrun.Enable(false) })
+
+	dir := t.TempDir()
+	if result := checkAddonFolder("plugins folder", dir); result.Status != StatusWarn {
+		t.Errorf("dry-run status = %s, want warn (%s)", result.Status, result.Message)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("dry-run probe created files: %v", entries)
+	}
+}
@@ -0,0 +1,103 @@
+// Package dryrun records the changes commands would make when the CLI runs
+// with --dry-run, instead of making them.
+package dryrun
+
+import (
+	"sync"
+
+	"github.com/betterdiscord/cli/internal/output"
+)
+
+// Kind is the type of change an action makes.
+type Kind string
+
+const (
+	CreateDir Kind = "create-dir"
+	Download  Kind = "download"
+	WriteFile Kind = "write-file"
+	Move      Kind = "move"
+	Remove    Kind = "remove"
+	Run       Kind = "run"
+	Kill      Kind = "kill"
+	Start     Kind = "start"
+)
+
+// Action is a single change that was skipped.
+type Action struct {
+	Kind   Kind   `json:"kind"`
+	Target string `json:"target"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// String describes the action for the plan.
+func (a Action) String() string {
+	var verb string
+	switch a.Kind {
+	case CreateDir:
+		verb = "create directory"
+	case Download:
+		verb = "download to"
+	case WriteFile:
+		verb = "write"
+	case Move:
+		verb = "move to"
+	case Remove:
+		verb = "remove"
+	case Run:
+		verb = "run"
+	case Kill:
+		verb = "kill"
+	case Start:
+		verb = "start"
+	default:
+		verb = string(a.Kind)
+	}
+
+	if a.Detail == "" {
+		return verb + " " + a.Target
+	}
+	return verb + " " + a.Target + " (" + a.Detail + ")"
+}
+
+var (
+	lock    sync.Mutex
+	enabled bool
+	actions []Action
+)
+
+// Enable turns dry-run mode on or off and clears any recorded actions.
+func Enable(on bool) {
+	lock.Lock()
+	defer lock.Unlock()
+	enabled = on
+	actions = nil
+}
+
+// Enabled reports whether dry-run mode is on.
+func Enabled() bool {
+	lock.Lock()
+	defer lock.Unlock()
+	return enabled
+}
+
+// Skip records an action and returns true when dry-run mode is on, in which
+// case the caller must not perform it. It returns false otherwise.
+func Skip(kind Kind, target, detail string) bool {
+	lock.Lock()
+	defer lock.Unlock()
+	if !enabled {
+		return false
+	}
+
+	action := Action{Kind: kind, Target: target, Detail: detail}
+	actions = append(actions, action)
+	output.Printf("📝 Would %s\n", action)
+	return true
+}
+
+// Actions returns the recorded actions in the order they were skipped.
+func Actions() []Action {
+	lock.Lock()
+	defer lock.Unlock()
+	return append([]Action{}, actions...)
+}
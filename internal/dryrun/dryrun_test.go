@@ -0,0 +1,49 @@
+package dryrun
+
+import (
+	"testing"
+)
+
+func TestSkip(t *testing.T) {
+	t.Cleanup(func() { Enable(false) })
+
+	Enable(false)
+	if Skip(Remove, "/bd", "") {
+		t.Error("Skip() should return false when dry-run is off")
+	}
+	if len(Actions()) != 0 {
+		t.Error("Skip() should not record actions when dry-run is off")
+	}
+
+	Enable(true)
+	if !Skip(CreateDir, "/bd/data", "") || !Skip(Download, "/bd/data/betterdiscord.asar", "https://example.com/asar") {
+		t.Error("Skip() should return true when dry-run is on")
+	}
+
+	actions := Actions()
+	if len(actions) != 2 || actions[0].Kind != CreateDir || actions[1].Target != "/bd/data/betterdiscord.asar" {
+		t.Errorf("Actions() = %+v", actions)
+	}
+
+	Enable(true)
+	if len(Actions()) != 0 {
+		t.Error("Enable() should clear recorded actions")
+	}
+}
+
+func TestActionString(t *testing.T) {
+	tests := []struct {
+		action   Action
+		expected string
+	}{
+		{Action{Kind: CreateDir, Target: "/bd/plugins"}, "create directory /bd/plugins"},
+		{Action{Kind: Download, Target: "/bd/data/betterdiscord.asar", Detail: "from https://example.com"}, "download to /bd/data/betterdiscord.asar (from https://example.com)"},
+		{Action{Kind: Kill, Target: "Discord"}, "kill Discord"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.action.String(); got != tt.expected {
+			t.Errorf("String() = %q, expected %q", got, tt.expected)
+		}
+	}
+}
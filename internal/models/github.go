@@ -71,6 +71,7 @@ type GitHubRelease struct {
 		CreatedAt          time.Time `json:"created_at"`
 		UpdatedAt          time.Time `json:"updated_at"`
 		BrowserDownloadURL string    `json:"browser_download_url"`
+		Digest             string    `json:"digest"`
 	} `json:"assets"`
 	TarballURL string `json:"tarball_url"`
 	ZipballURL string `json:"zipball_url"`
@@ -0,0 +1,160 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the rendering mode used for command results.
+type Format string
+
+const (
+	FormatTable Format = "table"
+	FormatJSON  Format = "json"
+	FormatYAML  Format = "yaml"
+)
+
+var (
+	format            = FormatTable
+	dataOut io.Writer = os.Stdout
+
+	// While holding, Emit keeps documents back so Release can add fields
+	// that are only known once the command has finished.
+	holding bool
+	held    []any
+)
+
+// ParseFormat converts user input into a Format.
+func ParseFormat(input string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(input))) {
+	case "", FormatTable:
+		return FormatTable, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	case FormatYAML, "yml":
+		return FormatYAML, nil
+	}
+	return FormatTable, fmt.Errorf("invalid output format %q (expected json, yaml, or table)", input)
+}
+
+// SetFormat sets the active output format.
+func SetFormat(f Format) {
+	format = f
+}
+
+// GetFormat returns the active output format.
+func GetFormat() Format {
+	return format
+}
+
+// IsStructured reports whether results should be emitted as a document
+// rather than human-friendly text.
+func IsStructured() bool {
+	return format == FormatJSON || format == FormatYAML
+}
+
+// SetDataWriter overrides the writer used for structured documents (useful for tests).
+// It is kept separate from the prose writer so --silent never hides data.
+func SetDataWriter(w io.Writer) {
+	if w != nil {
+		dataOut = w
+	}
+}
+
+// Emit writes v to stdout as a structured document in the active format.
+func Emit(v any) error {
+	if holding {
+		held = append(held, v)
+		return nil
+	}
+	return encode(dataOut, v)
+}
+
+// Hold makes Emit keep documents back until Release is called.
+func Hold() {
+	holding = true
+	held = nil
+}
+
+// Release writes the documents kept back since Hold, adding key with value
+// to the last one so stdout still holds a single document. A document that
+// is not an object is wrapped as {"result": ...}, and without any document
+// {key: value} is written on its own.
+func Release(key string, value any) error {
+	documents := held
+	holding, held = false, nil
+
+	if len(documents) == 0 {
+		return encode(dataOut, map[string]any{key: value})
+	}
+	for _, v := range documents[:len(documents)-1] {
+		if err := encode(dataOut, v); err != nil {
+			return err
+		}
+	}
+
+	merged, err := withField(documents[len(documents)-1], key, value)
+	if err != nil {
+		return err
+	}
+	return encode(dataOut, merged)
+}
+
+// withField adds key to the JSON object v, keeping the order of its fields.
+func withField(v any, key string, value any) (json.RawMessage, error) {
+	doc, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	field, err := json.Marshal(map[string]any{key: value})
+	if err != nil {
+		return nil, err
+	}
+
+	doc = bytes.TrimSpace(doc)
+	if len(doc) < 2 || doc[0] != '{' {
+		return json.Marshal(map[string]any{"result": json.RawMessage(doc), key: value})
+	}
+	if string(doc) == "{}" {
+		return field, nil
+	}
+	merged := append(doc[:len(doc)-1:len(doc)-1], ',')
+	merged = append(merged, field[1:]...)
+	return merged, nil
+}
+
+// EmitError writes err to stderr as a structured object in the active format.
+func EmitError(err error) error {
+	return encode(stdErr, map[string]string{"error": err.Error()})
+}
+
+func encode(w io.Writer, v any) error {
+	switch format {
+	case FormatYAML:
+		// Round-trip through JSON so YAML keys match the json struct tags
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		var generic any
+		if err := json.Unmarshal(raw, &generic); err != nil {
+			return err
+		}
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(generic); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+}
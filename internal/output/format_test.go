@@ -0,0 +1,136 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Format
+		wantErr  bool
+	}{
+		{"", FormatTable, false},
+		{"table", FormatTable, false},
+		{"JSON", FormatJSON, false},
+		{"yaml", FormatYAML, false},
+		{"yml", FormatYAML, false},
+		{"xml", FormatTable, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := ParseFormat(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFormat(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if result != tt.expected {
+				t.Errorf("ParseFormat(%q) = %s, expected %s", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEmit(t *testing.T) {
+	type doc struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+
+	tests := []struct {
+		format   Format
+		expected string
+	}{
+		{FormatJSON, "{\n  \"name\": \"Foo\",\n  \"version\": \"1.0.0\"\n}\n"},
+		{FormatYAML, "name: Foo\nversion: 1.0.0\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			SetDataWriter(&buf)
+			SetFormat(tt.format)
+			defer SetFormat(FormatTable)
+
+			if err := Emit(doc{Name: "Foo", Version: "1.0.0"}); err != nil {
+				t.Fatalf("Emit() failed: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("Emit() = %q, expected %q", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestHoldRelease(t *testing.T) {
+	type doc struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+
+	tests := []struct {
+		name     string
+		format   Format
+		docs     []any
+		expected string
+	}{
+		{"object", FormatJSON, []any{doc{Name: "Foo", Version: "1.0.0"}}, "{\n  \"name\": \"Foo\",\n  \"version\": \"1.0.0\",\n  \"plan\": [\n    \"a\"\n  ]\n}\n"},
+		{"list", FormatJSON, []any{[]string{"x"}}, "{\n  \"plan\": [\n    \"a\"\n  ],\n  \"result\": [\n    \"x\"\n  ]\n}\n"},
+		{"empty object", FormatJSON, []any{map[string]any{}}, "{\n  \"plan\": [\n    \"a\"\n  ]\n}\n"},
+		{"nothing emitted", FormatJSON, nil, "{\n  \"plan\": [\n    \"a\"\n  ]\n}\n"},
+		{"yaml", FormatYAML, []any{doc{Name: "Foo", Version: "1.0.0"}}, "name: Foo\nplan:\n  - a\nversion: 1.0.0\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			SetDataWriter(&buf)
+			SetFormat(tt.format)
+			defer SetFormat(FormatTable)
+
+			Hold()
+			for _, d := range tt.docs {
+				Emit(d) //nolint:errcheck
+			}
+			if buf.Len() != 0 {
+				t.Fatalf("Emit() wrote %q while holding", buf.String())
+			}
+			if err := Release("plan", []string{"a"}); err != nil {
+				t.Fatalf("Release() failed: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("Release() = %q, expected %q", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestEmitError(t *testing.T) {
+	var buf bytes.Buffer
+	SetWriters(nil, &buf)
+	SetFormat(FormatJSON)
+	defer SetFormat(FormatTable)
+
+	if err := EmitError(errors.New("something broke")); err != nil {
+		t.Fatalf("EmitError() failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"error": "something broke"`) {
+		t.Errorf("EmitError() = %q, expected error object", buf.String())
+	}
+}
+
+func TestIsStructured(t *testing.T) {
+	defer SetFormat(FormatTable)
+
+	SetFormat(FormatTable)
+	if IsStructured() {
+		t.Error("IsStructured() should be false for table format")
+	}
+	SetFormat(FormatJSON)
+	if !IsStructured() {
+		t.Error("IsStructured() should be true for json format")
+	}
+}
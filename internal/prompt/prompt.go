@@ -0,0 +1,49 @@
+// Package prompt asks the user to make choices on the terminal.
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// IsInteractive reports whether stdin is a terminal someone can answer from.
+func IsInteractive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// Select lists options numbered from 1 on out and reads the choice from in,
+// asking again until the answer is valid. An empty answer picks the first
+// option. It returns the index of the chosen option.
+func Select(in io.Reader, out io.Writer, question string, options []string) (int, error) {
+	if len(options) == 0 {
+		return -1, fmt.Errorf("nothing to choose from")
+	}
+
+	fmt.Fprintln(out, question)
+	for i, option := range options {
+		fmt.Fprintf(out, "  %d) %s\n", i+1, option)
+	}
+
+	reader := bufio.NewReader(in)
+	for {
+		fmt.Fprintf(out, "Choose 1-%d [1]: ", len(options))
+		line, err := reader.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if answer == "" && err == nil {
+			return 0, nil
+		}
+		if choice, convErr := strconv.Atoi(answer); convErr == nil && choice >= 1 && choice <= len(options) {
+			return choice - 1, nil
+		}
+		if err != nil {
+			return -1, fmt.Errorf("no choice made: %w", err)
+		}
+		fmt.Fprintf(out, "%q is not a valid choice\n", answer)
+	}
+}
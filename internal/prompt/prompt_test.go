@@ -0,0 +1,49 @@
+package prompt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSelect(t *testing.T) {
+	options := []string{"native", "flatpak", "snap"}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+		wantErr  bool
+	}{
+		{"first", "1\n", 0, false},
+		{"last", "3\n", 2, false},
+		{"default", "\n", 0, false},
+		{"padded", "  2  \n", 1, false},
+		{"retry after invalid", "7\nabc\n2\n", 1, false},
+		{"answer without newline", "3", 2, false},
+		{"no answer", "", -1, true},
+		{"only invalid answers", "9\n", -1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			choice, err := Select(strings.NewReader(tt.input), &out, "Which install?", options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Select() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if choice != tt.expected {
+				t.Errorf("Select() = %d, expected %d", choice, tt.expected)
+			}
+			if !strings.Contains(out.String(), "2) flatpak") {
+				t.Errorf("Select() did not list the options:\n%s", out.String())
+			}
+		})
+	}
+}
+
+func TestSelect_NoOptions(t *testing.T) {
+	if _, err := Select(strings.NewReader("1\n"), &bytes.Buffer{}, "Which install?", nil); err == nil {
+		t.Error("Select() with no options should fail")
+	}
+}
@@ -0,0 +1,107 @@
+// Package semver implements loose semantic version comparison for
+// BetterDiscord and addon version strings.
+//
+// Versions may have a leading "v", any number of dot-separated release parts
+// (missing parts count as zero, so "1.0" equals "1.0.0"), an optional
+// "-prerelease" suffix, and optional "+build" metadata which is ignored.
+package semver
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed loose semantic version.
+type Version struct {
+	Release    []string
+	Prerelease []string
+}
+
+// Parse splits a version string into its release and pre-release parts.
+// Parse never fails; unexpected input is compared as plain text.
+func Parse(input string) Version {
+	v := strings.TrimSpace(input)
+	v = strings.TrimPrefix(strings.TrimPrefix(v, "v"), "V")
+
+	if i := strings.Index(v, "+"); i >= 0 {
+		v = v[:i]
+	}
+
+	var version Version
+	if i := strings.Index(v, "-"); i >= 0 {
+		if pre := v[i+1:]; pre != "" {
+			version.Prerelease = strings.Split(pre, ".")
+		}
+		v = v[:i]
+	}
+
+	if v != "" {
+		version.Release = strings.Split(v, ".")
+	}
+	return version
+}
+
+// Compare returns -1 if a < b, 0 if they are equal, and 1 if a > b.
+func Compare(a, b string) int {
+	return Parse(a).Compare(Parse(b))
+}
+
+// Compare returns -1 if v < other, 0 if they are equal, and 1 if v > other.
+func (v Version) Compare(other Version) int {
+	for i := range max(len(v.Release), len(other.Release)) {
+		if c := compareIdentifier(part(v.Release, i), part(other.Release, i)); c != 0 {
+			return c
+		}
+	}
+
+	// A pre-release ranks below the release it precedes
+	switch {
+	case len(v.Prerelease) == 0 && len(other.Prerelease) == 0:
+		return 0
+	case len(v.Prerelease) == 0:
+		return 1
+	case len(other.Prerelease) == 0:
+		return -1
+	}
+
+	for i := range min(len(v.Prerelease), len(other.Prerelease)) {
+		if c := compareIdentifier(v.Prerelease[i], other.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInt(len(v.Prerelease), len(other.Prerelease))
+}
+
+func part(parts []string, i int) string {
+	if i < len(parts) {
+		return parts[i]
+	}
+	return "0"
+}
+
+// compareIdentifier compares numeric identifiers numerically and everything
+// else lexically, with numeric identifiers ranking below text.
+func compareIdentifier(a, b string) int {
+	an, aErr := strconv.Atoi(a)
+	bn, bErr := strconv.Atoi(b)
+
+	switch {
+	case aErr == nil && bErr == nil:
+		return compareInt(an, bn)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,51 @@
+package semver
+
+import "testing"
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{"1.0.156", "1.0.157", -1},
+		{"1.0.157", "1.0.156", 1},
+		{"1.0.0", "1.0.0", 0},
+		{"v1.0.0", "1.0.0", 0},
+		{"1.0", "1.0.0", 0},
+		{"1", "1.0.0", 0},
+		{"1.0.1", "1.0", 1},
+		{"0.0.100", "0.0.99", 1},
+		{"1.10.0", "1.9.0", 1},
+		{"1.0.0-beta", "1.0.0", -1},
+		{"1.0.0", "1.0.0-rc.1", 1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-alpha.beta", "1.0.0-beta", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"1.0.0-rc.1", "1.0.0-beta.11", 1},
+		{"1.0.0+build.5", "1.0.0+build.9", 0},
+		{"", "1.0.0", -1},
+		{"", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_vs_"+tt.b, func(t *testing.T) {
+			result := Compare(tt.a, tt.b)
+			if result != tt.expected {
+				t.Errorf("Compare(%q, %q) = %d, expected %d", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	v := Parse("v2.3.4-rc.1+build.7")
+
+	if len(v.Release) != 3 || v.Release[0] != "2" || v.Release[2] != "4" {
+		t.Errorf("Parse() release = %v, expected [2 3 4]", v.Release)
+	}
+	if len(v.Prerelease) != 2 || v.Prerelease[0] != "rc" || v.Prerelease[1] != "1" {
+		t.Errorf("Parse() prerelease = %v, expected [rc 1]", v.Prerelease)
+	}
+}
@@ -0,0 +1,206 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrNotCached is returned when a request cannot be answered in offline mode.
+var ErrNotCached = errors.New("not available in the offline cache")
+
+// Cache stores HTTP responses on disk and revalidates them with ETag and
+// If-Modified-Since once they are older than TTL. A zero TTL revalidates on
+// every request. In Offline mode only cached responses are returned, however
+// old they are.
+type Cache struct {
+	Dir     string
+	TTL     time.Duration
+	Offline bool
+	// OnStale is called when a stale entry is served because revalidating
+	// it failed.
+	OnStale func(url string, fetched time.Time, err error)
+}
+
+// CacheEntry is the on-disk representation of a cached response.
+type CacheEntry struct {
+	URL          string          `json:"url"`
+	ETag         string          `json:"etag,omitempty"`
+	LastModified string          `json:"lastModified,omitempty"`
+	Fetched      time.Time       `json:"fetched"`
+	Body         json.RawMessage `json:"body"`
+}
+
+// DefaultCacheDir returns the folder used for cached responses, or an empty
+// string if the user cache directory cannot be determined.
+func DefaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "bdcli")
+}
+
+// CachedJSON decodes the JSON response for url, served through cache when
+// one is configured.
+func CachedJSON[T any](cache *Cache, url string) (T, error) {
+	var data T
+	if cache == nil || cache.Dir == "" {
+		return DownloadJSON[T](url)
+	}
+
+	body, err := cache.Get(url)
+	if err != nil {
+		return data, err
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return data, err
+	}
+	return data, nil
+}
+
+// Get returns the body for url from the cache, revalidating or fetching it
+// as needed. If the network request fails a stale cached copy is returned
+// instead of the error, and OnStale is told about it.
+func (c *Cache) Get(url string) ([]byte, error) {
+	entry, _ := c.load(url)
+
+	if c.Offline || Offline {
+		if entry == nil {
+			return nil, fmt.Errorf("%s: %w", url, ErrNotCached)
+		}
+		return entry.Body, nil
+	}
+
+	if entry != nil && time.Since(entry.Fetched) < c.TTL {
+		return entry.Body, nil
+	}
+
+	fresh, err := c.fetch(url, entry)
+	if err != nil {
+		if entry != nil {
+			if c.OnStale != nil {
+				c.OnStale(url, entry.Fetched, err)
+			}
+			return entry.Body, nil
+		}
+		return nil, err
+	}
+
+	c.save(fresh) //nolint:errcheck
+	return fresh.Body, nil
+}
+
+// Clear removes every cached response.
+func (c *Cache) Clear() error {
+	if c.Dir == "" {
+		return nil
+	}
+	return os.RemoveAll(c.Dir)
+}
+
+// fetch requests url, sending the validators from a previous entry. A 304
+// response refreshes the previous entry instead of replacing it.
+func (c *Cache) fetch(url string, previous *CacheEntry) (entry *CacheEntry, err error) {
+	req, err := newRequest(url)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil {
+		if previous.ETag != "" {
+			req.Header.Add("If-None-Match", previous.ETag)
+		}
+		if previous.LastModified != "" {
+			req.Header.Add("If-Modified-Since", previous.LastModified)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotModified && previous != nil {
+		refreshed := *previous
+		refreshed.Fetched = time.Now()
+		return &refreshed, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bad status: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(body) {
+		return nil, fmt.Errorf("invalid JSON response from %s", url)
+	}
+
+	return &CacheEntry{
+		URL:          url,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Fetched:      time.Now(),
+		Body:         body,
+	}, nil
+}
+
+func (c *Cache) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *Cache) load(url string) (*CacheEntry, error) {
+	contents, err := os.ReadFile(c.path(url))
+	if err != nil {
+		return nil, err
+	}
+
+	var entry CacheEntry
+	if err := json.Unmarshal(contents, &entry); err != nil {
+		return nil, err
+	}
+	if entry.URL != url {
+		return nil, fmt.Errorf("cache entry does not match %s", url)
+	}
+	return &entry, nil
+}
+
+func (c *Cache) save(entry *CacheEntry) error {
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return err
+	}
+	contents, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// Write through a temp file so concurrent readers never see a partial entry
+	tmp, err := os.CreateTemp(c.Dir, ".entry.*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(contents); err != nil {
+		tmp.Close()           //nolint:errcheck
+		os.Remove(tmp.Name()) //nolint:errcheck
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name()) //nolint:errcheck
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(entry.URL))
+}
@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type cachedPayload struct {
+	Name string `json:"name"`
+}
+
+func newCacheServer(t *testing.T, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"name":"cached"}`)) //nolint:errcheck
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCachedJSON_FreshEntry(t *testing.T) {
+	var hits atomic.Int32
+	server := newCacheServer(t, &hits)
+	cache := &Cache{Dir: t.TempDir(), TTL: time.Hour}
+
+	for range 2 {
+		data, err := CachedJSON[cachedPayload](cache, server.URL)
+		if err != nil {
+			t.Fatalf("CachedJSON() failed: %v", err)
+		}
+		if data.Name != "cached" {
+			t.Errorf("Name = %s, expected cached", data.Name)
+		}
+	}
+	if hits.Load() != 1 {
+		t.Errorf("server was hit %d times, expected 1", hits.Load())
+	}
+}
+
+func TestCachedJSON_Revalidate(t *testing.T) {
+	var hits atomic.Int32
+	server := newCacheServer(t, &hits)
+	cache := &Cache{Dir: t.TempDir(), TTL: 0}
+
+	if _, err := CachedJSON[cachedPayload](cache, server.URL); err != nil {
+		t.Fatalf("CachedJSON() failed: %v", err)
+	}
+
+	// The second request carries the ETag and is answered with 304
+	data, err := CachedJSON[cachedPayload](cache, server.URL)
+	if err != nil {
+		t.Fatalf("CachedJSON() after revalidation failed: %v", err)
+	}
+	if data.Name != "cached" {
+		t.Errorf("Name = %s, expected cached", data.Name)
+	}
+	if hits.Load() != 2 {
+		t.Errorf("server was hit %d times, expected 2", hits.Load())
+	}
+}
+
+func TestCachedJSON_Offline(t *testing.T) {
+	var hits atomic.Int32
+	server := newCacheServer(t, &hits)
+	cache := &Cache{Dir: t.TempDir(), TTL: 0, Offline: true}
+
+	if _, err := CachedJSON[cachedPayload](cache, server.URL); !errors.Is(err, ErrNotCached) {
+		t.Fatalf("CachedJSON() error = %v, expected ErrNotCached", err)
+	}
+
+	cache.Offline = false
+	if _, err := CachedJSON[cachedPayload](cache, server.URL); err != nil {
+		t.Fatalf("CachedJSON() failed: %v", err)
+	}
+
+	cache.Offline = true
+	data, err := CachedJSON[cachedPayload](cache, server.URL)
+	if err != nil {
+		t.Fatalf("CachedJSON() offline failed: %v", err)
+	}
+	if data.Name != "cached" {
+		t.Errorf("Name = %s, expected cached", data.Name)
+	}
+	if hits.Load() != 1 {
+		t.Errorf("server was hit %d times, expected 1", hits.Load())
+	}
+}
+
+func TestCachedJSON_StaleOnError(t *testing.T) {
+	var hits atomic.Int32
+	server := newCacheServer(t, &hits)
+	var stale []string
+	cache := &Cache{Dir: t.TempDir(), TTL: 0, OnStale: func(url string, fetched time.Time, err error) {
+		stale = append(stale, url)
+	}}
+
+	if _, err := CachedJSON[cachedPayload](cache, server.URL); err != nil {
+		t.Fatalf("CachedJSON() failed: %v", err)
+	}
+	if len(stale) != 0 {
+		t.Errorf("OnStale called for a fresh response: %v", stale)
+	}
+	server.Close()
+
+	data, err := CachedJSON[cachedPayload](cache, server.URL)
+	if err != nil {
+		t.Fatalf("CachedJSON() should fall back to the stale entry: %v", err)
+	}
+	if data.Name != "cached" {
+		t.Errorf("Name = %s, expected cached", data.Name)
+	}
+	if len(stale) != 1 || stale[0] != server.URL {
+		t.Errorf("OnStale calls = %v, expected one for %s", stale, server.URL)
+	}
+}
+
+func TestCache_Clear(t *testing.T) {
+	var hits atomic.Int32
+	server := newCacheServer(t, &hits)
+	cache := &Cache{Dir: t.TempDir(), TTL: time.Hour}
+
+	if _, err := CachedJSON[cachedPayload](cache, server.URL); err != nil {
+		t.Fatalf("CachedJSON() failed: %v", err)
+	}
+	if err := cache.Clear(); err != nil {
+		t.Fatalf("Clear() failed: %v", err)
+	}
+
+	cache.Offline = true
+	if _, err := CachedJSON[cachedPayload](cache, server.URL); !errors.Is(err, ErrNotCached) {
+		t.Errorf("CachedJSON() after Clear() error = %v, expected ErrNotCached", err)
+	}
+}
@@ -2,10 +2,12 @@ package utils
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"time"
 )
 
@@ -13,25 +15,51 @@ var client = &http.Client{
 	Timeout: 10 * time.Second,
 }
 
-func DownloadFile(url string, filepath string) (response *http.Response, err error) {
+// Offline makes every download fail with ErrOffline instead of reaching the network.
+var Offline bool
 
-	// Create the file
-	out, err := os.Create(filepath)
+// ErrOffline is returned for downloads attempted in offline mode.
+var ErrOffline = errors.New("network access is disabled in offline mode")
+
+// newRequest builds a GET request for url, failing fast in offline mode.
+func newRequest(url string) (*http.Request, error) {
+	if Offline {
+		return nil, fmt.Errorf("%s: %w", url, ErrOffline)
+	}
+	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		if cerr := out.Close(); cerr != nil && err == nil {
-			err = cerr
-		}
-	}()
+	req.Header.Add("User-Agent", "BetterDiscord/cli")
+	return req, nil
+}
+
+// DownloadFile downloads url to destination without ever leaving a partial
+// file behind. See DownloadFileVerified.
+func DownloadFile(url string, destination string) (response *http.Response, err error) {
+	return DownloadFileVerified(url, destination, nil)
+}
 
+// DownloadFileVerified streams url into a temporary file in the same directory
+// as destination, fsyncs it, runs the optional verify callback against the
+// temporary path, and only then renames it over destination. If anything fails,
+// the existing destination is left untouched.
+func DownloadFileVerified(url string, destination string, verify func(tmpPath string) error) (response *http.Response, err error) {
+	var check func(*http.Response, string) error
+	if verify != nil {
+		check = func(_ *http.Response, tmpPath string) error { return verify(tmpPath) }
+	}
+	return DownloadFileChecked(url, destination, check)
+}
+
+// DownloadFileChecked is DownloadFileVerified with the response also passed to
+// verify, for checks that depend on the response headers.
+func DownloadFileChecked(url string, destination string, verify func(resp *http.Response, tmpPath string) error) (response *http.Response, err error) {
 	// Setup the request
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := newRequest(url)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Add("User-Agent", "BetterDiscord/cli")
 	req.Header.Add("Accept", "application/octet-stream")
 
 	// Get the data
@@ -50,11 +78,52 @@ func DownloadFile(url string, filepath string) (response *http.Response, err err
 		return resp, fmt.Errorf("bad status code: %s", resp.Status)
 	}
 
-	// Writer the body to file
-	_, err = io.Copy(out, resp.Body)
+	// Create the temp file next to the destination so the rename is atomic
+	out, err := os.CreateTemp(filepath.Dir(destination), "."+filepath.Base(destination)+".*.tmp")
 	if err != nil {
 		return resp, err
 	}
+	tmpPath := out.Name()
+	defer func() {
+		if err != nil {
+			out.Close()        //nolint:errcheck
+			os.Remove(tmpPath) //nolint:errcheck
+		}
+	}()
+
+	// Write the body to file
+	if _, err = io.Copy(out, resp.Body); err != nil {
+		return resp, err
+	}
+	if err = out.Sync(); err != nil {
+		return resp, err
+	}
+	if err = out.Close(); err != nil {
+		return resp, err
+	}
+
+	// Temp files are private by default, match what os.Create would have produced
+	mode := os.FileMode(0644)
+	if info, statErr := os.Stat(destination); statErr == nil {
+		mode = info.Mode().Perm()
+	}
+	if err = os.Chmod(tmpPath, mode); err != nil {
+		return resp, err
+	}
+
+	if verify != nil {
+		if err = verify(resp, tmpPath); err != nil {
+			return resp, err
+		}
+	}
+
+	if err = os.Rename(tmpPath, destination); err != nil {
+		return resp, err
+	}
+	// The rename is only durable once the directory entry is on disk
+	if err = SyncDir(filepath.Dir(destination)); err != nil {
+		return resp, err
+	}
 
 	return resp, nil
 }
@@ -63,11 +132,10 @@ func DownloadJSON[T any](url string) (T, error) {
 	var data T
 
 	// Setup the request
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := newRequest(url)
 	if err != nil {
 		return data, err
 	}
-	req.Header.Add("User-Agent", "BetterDiscord/cli")
 
 	// Get the data
 	resp, err := client.Do(req)
@@ -93,3 +161,33 @@ func DownloadJSON[T any](url string) (T, error) {
 
 	return data, nil
 }
+
+// DownloadText fetches a small text resource such as a checksums file.
+func DownloadText(url string) (text string, err error) {
+	req, err := newRequest(url)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Add("Accept", "application/octet-stream")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("bad status: %s", resp.Status)
+	}
+
+	// Checksum files are tiny, anything larger is not what we expect
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
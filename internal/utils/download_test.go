@@ -2,10 +2,12 @@ package utils
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 )
@@ -98,6 +100,98 @@ func TestDownloadFile_InvalidPath(t *testing.T) {
 	}
 }
 
+func TestDownloadFile_BadStatusKeepsExisting(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "existing.txt")
+	if err := os.WriteFile(testFile, []byte("original"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	if _, err := DownloadFile(server.URL, testFile); err == nil {
+		t.Fatal("DownloadFile() should have returned an error for 500 status")
+	}
+
+	content, _ := os.ReadFile(testFile)
+	if string(content) != "original" {
+		t.Errorf("Existing file was modified on failure, got '%s'", string(content))
+	}
+
+	entries, _ := os.ReadDir(tmpDir)
+	if len(entries) != 1 {
+		t.Errorf("Expected no leftover temp files, found %d entries", len(entries))
+	}
+}
+
+func TestDownloadFileVerified_RejectKeepsExisting(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("replacement")) //nolint:errcheck
+	}))
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "existing.txt")
+	if err := os.WriteFile(testFile, []byte("original"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	var seen string
+	_, err := DownloadFileVerified(server.URL, testFile, func(tmpPath string) error {
+		content, _ := os.ReadFile(tmpPath)
+		seen = string(content)
+		return errors.New("rejected")
+	})
+	if err == nil {
+		t.Fatal("DownloadFileVerified() should return the verify error")
+	}
+
+	if seen != "replacement" {
+		t.Errorf("verify saw '%s', expected 'replacement'", seen)
+	}
+
+	content, _ := os.ReadFile(testFile)
+	if string(content) != "original" {
+		t.Errorf("Existing file was modified on rejected download, got '%s'", string(content))
+	}
+
+	entries, _ := os.ReadDir(tmpDir)
+	if len(entries) != 1 {
+		t.Errorf("Expected no leftover temp files, found %d entries", len(entries))
+	}
+}
+
+func TestDownloadFile_PreservesMode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("replacement")) //nolint:errcheck
+	}))
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "existing.txt")
+	if err := os.WriteFile(testFile, []byte("original"), 0640); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	os.Chmod(testFile, 0640) //nolint:errcheck
+
+	if _, err := DownloadFile(server.URL, testFile); err != nil {
+		t.Fatalf("DownloadFile() failed: %v", err)
+	}
+
+	info, err := os.Stat(testFile)
+	if err != nil {
+		t.Fatalf("Failed to stat downloaded file: %v", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0640 {
+		t.Errorf("Expected mode 0640, got %o", info.Mode().Perm())
+	}
+}
+
 func TestDownloadJSON(t *testing.T) {
 	// Define a test struct
 	type TestData struct {
@@ -228,3 +322,32 @@ func TestDownloadJSON_InvalidURL(t *testing.T) {
 		t.Error("DownloadJSON() should have returned an error for invalid URL")
 	}
 }
+
+func TestDownload_Offline(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte(`{}`)) //nolint:errcheck
+	}))
+	t.Cleanup(server.Close)
+
+	Offline = true
+	t.Cleanup(func() { Offline = false })
+
+	dest := filepath.Join(t.TempDir(), "file")
+	if _, err := DownloadFile(server.URL, dest); !errors.Is(err, ErrOffline) {
+		t.Errorf("DownloadFile() error = %v, expected ErrOffline", err)
+	}
+	if _, err := DownloadJSON[map[string]any](server.URL); !errors.Is(err, ErrOffline) {
+		t.Errorf("DownloadJSON() error = %v, expected ErrOffline", err)
+	}
+	if _, err := DownloadText(server.URL); !errors.Is(err, ErrOffline) {
+		t.Errorf("DownloadText() error = %v, expected ErrOffline", err)
+	}
+	if hits != 0 {
+		t.Errorf("offline downloads reached the server %d time(s)", hits)
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Error("nothing should be written in offline mode")
+	}
+}
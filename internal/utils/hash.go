@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"strings"
+)
+
+// SHA256File returns the hex-encoded SHA-256 digest of the file at path.
+func SHA256File(path string) (hash string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if cerr := f.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// NormalizeSHA256 lowercases a hex digest and strips an optional "sha256:" prefix.
+func NormalizeSHA256(digest string) string {
+	digest = strings.ToLower(strings.TrimSpace(digest))
+	return strings.TrimPrefix(digest, "sha256:")
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSHA256File(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "test.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	got, err := SHA256File(path)
+	if err != nil {
+		t.Fatalf("SHA256File() failed: %v", err)
+	}
+
+	expected := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	if got != expected {
+		t.Errorf("SHA256File() = %s, expected %s", got, expected)
+	}
+}
+
+func TestSHA256File_Missing(t *testing.T) {
+	if _, err := SHA256File(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("SHA256File() should fail for a missing file")
+	}
+}
+
+func TestNormalizeSHA256(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"sha256:ABCDEF", "abcdef"},
+		{"  abcdef  ", "abcdef"},
+		{"SHA256:abc", "abc"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := NormalizeSHA256(tt.input)
+			if result != tt.expected {
+				t.Errorf("NormalizeSHA256(%q) = %q, expected %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package utils
+
+import "sync"
+
+// ParallelMap applies fn to every item using at most jobs goroutines and
+// returns the results in the same order as items.
+func ParallelMap[T any, R any](items []T, jobs int, fn func(T) R) []R {
+	results := make([]R, len(items))
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(jobs, len(items)) {
+		wg.Go(func() {
+			for i := range indexes {
+				results[i] = fn(items[i])
+			}
+		})
+	}
+
+	for i := range items {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
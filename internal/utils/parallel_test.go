@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParallelMap_PreservesOrder(t *testing.T) {
+	input := []int{5, 1, 4, 2, 3}
+
+	result := ParallelMap(input, 3, func(n int) int {
+		time.Sleep(time.Duration(n) * time.Millisecond)
+		return n * 10
+	})
+
+	expected := []int{50, 10, 40, 20, 30}
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Errorf("result[%d] = %d, expected %d", i, result[i], expected[i])
+		}
+	}
+}
+
+func TestParallelMap_BoundsConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	input := make([]int, 20)
+
+	ParallelMap(input, 4, func(n int) int {
+		current := running.Add(1)
+		for {
+			old := peak.Load()
+			if current <= old || peak.CompareAndSwap(old, current) {
+				break
+			}
+		}
+		time.Sleep(2 * time.Millisecond)
+		running.Add(-1)
+		return n
+	})
+
+	if peak.Load() > 4 {
+		t.Errorf("Expected at most 4 concurrent jobs, saw %d", peak.Load())
+	}
+}
+
+func TestParallelMap_Empty(t *testing.T) {
+	result := ParallelMap([]int{}, 4, func(n int) int { return n })
+	if len(result) != 0 {
+		t.Errorf("Expected empty result, got %v", result)
+	}
+}
+
+func TestParallelMap_InvalidJobs(t *testing.T) {
+	result := ParallelMap([]int{1, 2}, 0, func(n int) int { return n + 1 })
+	if result[0] != 2 || result[1] != 3 {
+		t.Errorf("Expected jobs < 1 to fall back to serial execution, got %v", result)
+	}
+}
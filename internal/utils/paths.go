@@ -1,7 +1,10 @@
 package utils
 
 import (
+	"io"
 	"os"
+	"path/filepath"
+	"runtime"
 )
 
 func Exists(path string) bool {
@@ -18,3 +21,88 @@ func Filter[T any](source []T, filterFunc func(T) bool) (ret []T) {
 	}
 	return returnArray
 }
+
+// CopyFile copies the contents and permissions of src to dst, replacing dst if it exists.
+func CopyFile(src, dst string) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := in.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := out.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	_, err = io.Copy(out, in)
+	return err
+}
+
+// WriteFileAtomic writes data to a temporary file next to dst and renames it
+// over dst, so dst is either left untouched or fully replaced. A symlink at
+// dst is replaced rather than written through. An existing dst keeps its
+// permissions, otherwise perm is used.
+func WriteFileAtomic(dst string, data []byte, perm os.FileMode) (err error) {
+	out, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := out.Name()
+	defer func() {
+		if err != nil {
+			out.Close()        //nolint:errcheck
+			os.Remove(tmpPath) //nolint:errcheck
+		}
+	}()
+
+	if _, err = out.Write(data); err != nil {
+		return err
+	}
+	if err = out.Sync(); err != nil {
+		return err
+	}
+	if err = out.Close(); err != nil {
+		return err
+	}
+
+	if info, statErr := os.Stat(dst); statErr == nil {
+		perm = info.Mode().Perm()
+	}
+	if err = os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+	if err = os.Rename(tmpPath, dst); err != nil {
+		return err
+	}
+	return SyncDir(filepath.Dir(dst))
+}
+
+// SyncDir flushes the directory entry changes in dir to disk, so a rename
+// into it survives a crash. Windows cannot sync directories and persists
+// renames on its own, so it is a no-op there.
+func SyncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close() //nolint:errcheck
+	return d.Sync()
+}
@@ -145,3 +145,62 @@ func TestFilterWithStructs(t *testing.T) {
 		t.Errorf("Filter() returned unexpected persons: %v", result)
 	}
 }
+
+func TestCopyFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	src := filepath.Join(tmpDir, "src.txt")
+	dst := filepath.Join(tmpDir, "dst.txt")
+
+	if err := os.WriteFile(src, []byte("contents"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := os.WriteFile(dst, []byte("a much longer previous contents"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	if err := CopyFile(src, dst); err != nil {
+		t.Fatalf("CopyFile() failed: %v", err)
+	}
+
+	content, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatalf("Failed to read copied file: %v", err)
+	}
+	if string(content) != "contents" {
+		t.Errorf("CopyFile() wrote '%s', expected 'contents'", string(content))
+	}
+
+	if err := CopyFile(filepath.Join(tmpDir, "missing.txt"), dst); err == nil {
+		t.Error("CopyFile() should fail for a missing source")
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	tmpDir := t.TempDir()
+	original := filepath.Join(tmpDir, "original.txt")
+	link := filepath.Join(tmpDir, "link.txt")
+	os.WriteFile(original, []byte("original"), 0600) //nolint:errcheck
+	os.Symlink(original, link)                       //nolint:errcheck
+
+	if err := WriteFileAtomic(link, []byte("replaced"), 0644); err != nil {
+		t.Fatalf("WriteFileAtomic() failed: %v", err)
+	}
+
+	contents, _ := os.ReadFile(original)
+	if string(contents) != "original" {
+		t.Errorf("WriteFileAtomic() wrote through the symlink, original = %q", string(contents))
+	}
+	info, err := os.Lstat(link)
+	if err != nil || info.Mode()&os.ModeSymlink != 0 {
+		t.Fatalf("the symlink should be replaced by a file, got %v, %v", info, err)
+	}
+	contents, _ = os.ReadFile(link)
+	if string(contents) != "replaced" {
+		t.Errorf("WriteFileAtomic() contents = %q", string(contents))
+	}
+
+	entries, _ := os.ReadDir(tmpDir)
+	if len(entries) != 2 {
+		t.Errorf("temporary files were left behind, found %d entries", len(entries))
+	}
+}